@@ -0,0 +1,96 @@
+package gocmd
+
+import (
+	"time"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+const (
+	// IndexCacheEnv is the environment variable to configure the directory of the cache of the release index,
+	// which is used when SetIndexCache is not called. "off" disables the cache.
+	IndexCacheEnv = internal.CacheEnv
+
+	// DefaultIndexCacheTTL is the default period in which the cached release index is used without any request.
+	DefaultIndexCacheTTL = internal.DefaultCacheTTL
+)
+
+// IndexCacheEntry is the release index cached on disk.
+type IndexCacheEntry struct {
+	// Source is the URL of the release index.
+	Source string
+
+	// Path is the path of the cache file.
+	Path string
+
+	// ETag and LastModified are sent in the conditional request after the TTL expires.
+	ETag         string
+	LastModified string
+
+	// FetchedAt is the time when the index is fetched or revalidated.
+	FetchedAt time.Time
+
+	// Size is the size of the index.
+	Size int
+}
+
+// SetIndexCache sets the directory and TTL of the cache of the release index fetched from HTTP(S) source.
+// The cached index is used without any request within ttl, so that short-lived processes do not fetch the index every time.
+// After that, it is revalidated with ETag and If-Modified-Since.
+//
+// Empty dir resets the directory to the value of IndexCacheEnv, or "gocmd" in os.UserCacheDir.
+// If dir is "off", the cache is disabled. Non-positive ttl resets it to DefaultIndexCacheTTL.
+func SetIndexCache(dir string, ttl time.Duration) {
+	defaultResolver.SetIndexCache(dir, ttl)
+}
+
+// SetIndexCache sets the directory and the TTL of the cache which r fetches the release index through.
+func (r *Resolver) SetIndexCache(dir string, ttl time.Duration) {
+	r.catalog.SetCache(dir, ttl)
+}
+
+// IndexCacheDir returns the directory of the cache of the release index.
+// It returns an empty string if the cache is disabled.
+func IndexCacheDir() string {
+	return defaultResolver.IndexCacheDir()
+}
+
+// IndexCacheDir returns the cache directory of the release index of r, or an empty string if the cache is disabled.
+func (r *Resolver) IndexCacheDir() string {
+	return r.catalog.CacheDir()
+}
+
+// IndexCacheEntries returns the cached release indexes.
+func IndexCacheEntries() ([]IndexCacheEntry, error) {
+	return defaultResolver.IndexCacheEntries()
+}
+
+// IndexCacheEntries lists the release indexes cached in the cache directory of r.
+func (r *Resolver) IndexCacheEntries() ([]IndexCacheEntry, error) {
+	entries, err := r.catalog.CacheEntries()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]IndexCacheEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, IndexCacheEntry{
+			Source:       e.Source,
+			Path:         e.Path,
+			ETag:         e.ETag,
+			LastModified: e.LastModified,
+			FetchedAt:    e.FetchedAt,
+			Size:         len(e.Data),
+		})
+	}
+	return list, nil
+}
+
+// ClearIndexCache removes the cached release indexes.
+func ClearIndexCache() error {
+	return defaultResolver.ClearIndexCache()
+}
+
+// ClearIndexCache removes the release indexes cached in the cache directory of r.
+func (r *Resolver) ClearIndexCache() error {
+	return r.catalog.ClearCache()
+}
@@ -0,0 +1,88 @@
+package gocmd
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSetIndexCache(t *testing.T) {
+	const etag = `"v1"`
+	version := futureVersion(1)
+	index := filepath.Join(t.TempDir(), "index.json")
+	writeReleaseIndex(t, index, version)
+	var requested, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		http.ServeFile(w, r, index)
+	}))
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+	dir := t.TempDir()
+	r.SetIndexCache(dir, time.Hour)
+	if got := r.IndexCacheDir(); got != dir {
+		t.Fatalf("unexpected cache directory: %s", got)
+	}
+
+	err := r.ValidVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := r.IndexCacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].ETag != etag || entries[0].Source != srv.URL+"/dl/?mode=json&include=all" {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+
+	// within TTL, the cached index is used without any request
+	r = newIndexResolver(t, srv.URL+"/dl/")
+	r.SetIndexCache(dir, time.Hour)
+	err = r.ValidVersion(futureVersion(2))
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested != 1 {
+		t.Fatalf("unexpected number of requests: %d", requested)
+	}
+
+	// after TTL, the cached index is revalidated
+	r = newIndexResolver(t, srv.URL+"/dl/")
+	r.SetIndexCache(dir, time.Nanosecond)
+	err = r.ValidVersion(futureVersion(2))
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if requested != 2 || notModified != 1 {
+		t.Fatalf("unexpected number of requests: %d (not modified: %d)", requested, notModified)
+	}
+
+	err = r.ClearIndexCache()
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err = r.IndexCacheEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("unexpected entries: %#v", entries)
+	}
+}
+
+func TestSetIndexCache_off(t *testing.T) {
+	t.Setenv(IndexCacheEnv, "off")
+	if got := NewResolver().IndexCacheDir(); got != "" {
+		t.Fatalf("unexpected cache directory: %s", got)
+	}
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// checkFile generates the content in memory, and reports how the existing file differs from it.
+// The timestamp of the existing snapshot is kept, so that only the releases and the formatting are checked.
+func checkFile(d *Diff, list []internal.Release) error {
+	current, err := os.ReadFile(dst)
+	if err != nil {
+		return err
+	}
+	var want []byte
+	if outFormat == "json" {
+		var generatedAt time.Time
+		if s, err := internal.ParseSnapshot(current); err == nil {
+			generatedAt = s.GeneratedAt
+		}
+		want, err = marshalSnapshot(generatedAt, list)
+	} else {
+		want, err = generateGo(list)
+	}
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, want) {
+		return nil
+	}
+
+	buf := bytes.NewBuffer(nil)
+	_, _ = fmt.Fprintf(buf, "%s is not up to date:\n", dst)
+	if !d.Empty() {
+		out, err := formatDiff(d, "text")
+		if err != nil {
+			return err
+		}
+		buf.Write(out)
+	} else {
+		// same versions, but edited by hand
+		writeFirstDiff(buf, current, want)
+	}
+	return errors.New(buf.String())
+}
+
+// checkConsts reports how the existing file of the constants differs from the generated one.
+func checkConsts(want []byte) error {
+	current, err := os.ReadFile(consts)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(current, want) {
+		return nil
+	}
+	buf := bytes.NewBuffer(nil)
+	_, _ = fmt.Fprintf(buf, "%s is not up to date:\n", consts)
+	writeFirstDiff(buf, current, want)
+	return errors.New(buf.String())
+}
+
+func writeFirstDiff(buf *bytes.Buffer, current, want []byte) {
+	line, got, expected := firstDiff(current, want)
+	_, _ = fmt.Fprintf(buf, "line %d:\n- %s\n+ %s\n", line, got, expected)
+}
+
+// firstDiff returns the first line that differs between a and b.
+func firstDiff(a, b []byte) (line int, la, lb string) {
+	linesA := strings.Split(string(a), "\n")
+	linesB := strings.Split(string(b), "\n")
+	for i := 0; ; i++ {
+		if i >= len(linesA) || i >= len(linesB) {
+			if i < len(linesA) {
+				la = linesA[i]
+			}
+			if i < len(linesB) {
+				lb = linesB[i]
+			}
+			return i + 1, la, lb
+		}
+		if linesA[i] != linesB[i] {
+			return i + 1, linesA[i], linesB[i]
+		}
+	}
+}
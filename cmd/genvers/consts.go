@@ -0,0 +1,72 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"sort"
+	"strings"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// constName returns the name of the constant for the version, such as "Go1_21_5" for "go1.21.5".
+func constName(v string) string {
+	return "Go" + strings.ReplaceAll(strings.TrimPrefix(v, "go"), ".", "_")
+}
+
+// generateConsts generates the constants of type Version for each release, and Latest and LatestStable.
+func generateConsts(list []internal.Release) ([]byte, error) {
+	type release struct {
+		name    string
+		version string
+		stable  bool
+	}
+	var rs []release
+	for _, r := range list {
+		// gocmd is not imported, so that genvers can regenerate the constants even if they are broken
+		if _, ok := internal.ParseGoVersion(r.Version); !ok {
+			// not a Go release
+			continue
+		}
+		rs = append(rs, release{name: constName(r.Version), version: r.Version, stable: r.Stable})
+	}
+	if len(rs) == 0 {
+		return nil, fmt.Errorf("no release to generate constants")
+	}
+	sort.SliceStable(rs, func(i, j int) bool {
+		return internal.CompareGoVersions(rs[i].version, rs[j].version) < 0
+	})
+	latest := rs[len(rs)-1]
+	var latestStable *release
+	for i := len(rs) - 1; i >= 0; i-- {
+		if rs[i].stable {
+			latestStable = &rs[i]
+			break
+		}
+	}
+
+	buf := bytes.NewBuffer(nil)
+	_, _ = fmt.Fprintf(buf, `// Code generated by genvers. DO NOT EDIT.
+package %s
+
+// Releases of Go in the embedded release index.
+// Referring to them instead of string literals, pinned versions are checked at compile time.
+const (
+`, constsPkg)
+	for _, r := range rs {
+		_, _ = fmt.Fprintf(buf, "%s %s = %q\n", r.name, constsType, r.version)
+	}
+	_, _ = fmt.Fprintf(buf, `)
+
+// Latest is the newest release in the embedded release index, including betas and release candidates.
+const Latest = %s
+`, latest.name)
+	if latestStable != nil {
+		_, _ = fmt.Fprintf(buf, `
+// LatestStable is the newest stable release in the embedded release index.
+const LatestStable = %s
+`, latestStable.name)
+	}
+	return format.Source(buf.Bytes())
+}
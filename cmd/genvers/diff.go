@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// Diff is the difference between the existing snapshot and the fetched release index.
+type Diff struct {
+	Added   []Change `json:"added"`
+	Removed []Change `json:"removed"`
+
+	// Flipped is the releases whose stability changed. Stable is the new one.
+	Flipped []Change `json:"flipped"`
+}
+
+// Change is a release added, removed or flipped.
+type Change struct {
+	Version string `json:"version"`
+	Stable  bool   `json:"stable"`
+}
+
+// Empty reports whether no version is changed.
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Flipped) == 0
+}
+
+func diff(old, list []internal.Release) *Diff {
+	oldVersions := map[string]bool{}
+	for _, r := range old {
+		oldVersions[r.Version] = r.Stable
+	}
+	newVersions := map[string]bool{}
+	for _, r := range list {
+		newVersions[r.Version] = r.Stable
+	}
+
+	d := &Diff{
+		Added:   []Change{},
+		Removed: []Change{},
+		Flipped: []Change{},
+	}
+	for v, stable := range newVersions {
+		oldStable, ok := oldVersions[v]
+		if !ok {
+			d.Added = append(d.Added, Change{Version: v, Stable: stable})
+		} else if oldStable != stable {
+			d.Flipped = append(d.Flipped, Change{Version: v, Stable: stable})
+		}
+	}
+	for v, stable := range oldVersions {
+		if _, ok := newVersions[v]; !ok {
+			d.Removed = append(d.Removed, Change{Version: v, Stable: stable})
+		}
+	}
+	for _, c := range [][]Change{d.Added, d.Removed, d.Flipped} {
+		sort.Slice(c, func(i, j int) bool {
+			return c[i].Version < c[j].Version
+		})
+	}
+	return d
+}
+
+// formatDiff formats the diff as "text", "json" or "markdown".
+// The markdown is used as the body of the pull request, and then CHANGELOG.md.
+func formatDiff(d *Diff, format string) ([]byte, error) {
+	buf := bytes.NewBuffer(nil)
+	switch format {
+	case "text":
+		if d.Empty() {
+			buf.WriteString("no changes\n")
+		}
+		for _, c := range d.Added {
+			_, _ = fmt.Fprintf(buf, "+ %s%s\n", c.Version, stability(c.Stable))
+		}
+		for _, c := range d.Removed {
+			_, _ = fmt.Fprintf(buf, "- %s%s\n", c.Version, stability(c.Stable))
+		}
+		for _, c := range d.Flipped {
+			_, _ = fmt.Fprintf(buf, "~ %s%s\n", c.Version, stability(c.Stable))
+		}
+	case "json":
+		out, err := json.MarshalIndent(d, "", "\t")
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(out)
+		buf.WriteByte('\n')
+	case "markdown":
+		if d.Empty() {
+			buf.WriteString("No version of Go was changed.\n")
+		}
+		section := func(title string, changes []Change) {
+			if len(changes) == 0 {
+				return
+			}
+			_, _ = fmt.Fprintf(buf, "### %s\n", title)
+			for _, c := range changes {
+				_, _ = fmt.Fprintf(buf, "- %s%s\n", c.Version, stability(c.Stable))
+			}
+			buf.WriteByte('\n')
+		}
+		section("Added", d.Added)
+		section("Removed", d.Removed)
+		section("Stability changed", d.Flipped)
+	default:
+		return nil, fmt.Errorf("unknown diff format: %s", format)
+	}
+	return buf.Bytes(), nil
+}
+
+func stability(stable bool) string {
+	if stable {
+		return " (stable)"
+	}
+	return " (unstable)"
+}
+
+// readExisting reads the releases in the existing output file.
+// If the file does not exist, it returns nil.
+// Files of the releases are read only from the snapshot of "json" format.
+func readExisting(path, format string) ([]internal.Release, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if format == "json" {
+		s, err := internal.ParseSnapshot(data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		return s.Releases, nil
+	}
+	return parseGo(path, data)
+}
+
+// parseGo reads the map of versions generated by generateGo.
+func parseGo(path string, data []byte) ([]internal.Release, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), path, data, 0)
+	if err != nil {
+		return nil, err
+	}
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.VAR {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			vs := spec.(*ast.ValueSpec)
+			if len(vs.Names) != 1 || vs.Names[0].Name != varName || len(vs.Values) != 1 {
+				continue
+			}
+			lit, ok := vs.Values[0].(*ast.CompositeLit)
+			if !ok {
+				break
+			}
+			var list []internal.Release
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					return nil, fmt.Errorf("%s: unexpected element of %s", path, varName)
+				}
+				key, ok := kv.Key.(*ast.BasicLit)
+				value, ok2 := kv.Value.(*ast.Ident)
+				if !ok || !ok2 {
+					return nil, fmt.Errorf("%s: unexpected element of %s", path, varName)
+				}
+				v, err := strconv.Unquote(key.Value)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, internal.Release{
+					Version: v,
+					Stable:  value.Name == "true",
+				})
+			}
+			return list, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: variable %s not found", path, varName)
+}
@@ -2,24 +2,46 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
 	"go/format"
 	"log"
 	"os"
+	"reflect"
 	"sort"
+	"strconv"
+	"time"
 
 	"github.com/daichitakahashi/gocmd/internal"
 )
 
 var (
-	dst, pkg, varName string
+	dst, pkg, varName, filesVarName string
+	index                           string
+	outFormat                       string
+	diffOut, diffFormat             string
+	force, check                    bool
+	src                             string
+	consts, constsPkg, constsType   string
 )
 
 func init() {
 	flag.StringVar(&dst, "dst", "", "out put file path")
-	flag.StringVar(&pkg, "pkg", "", "out put package name")
-	flag.StringVar(&varName, "var", "", "out put variable name")
+	flag.StringVar(&pkg, "pkg", "", "out put package name (go format only)")
+	flag.StringVar(&varName, "var", "", "out put variable name (go format only)")
+	flag.StringVar(&filesVarName, "files", "", "out put variable name of release files (go format only, optional)")
+	flag.StringVar(&index, "index", "", "source of release index: URL, file:// URL, file or directory (default $"+internal.SourceEnv+" or "+internal.DefaultSource+")")
+	flag.StringVar(&outFormat, "format", "go", `out put format: "go" for Go source, or "json" for the snapshot embedded with go:embed`)
+	flag.StringVar(&diffOut, "diff", "", `out put file path of the diff against the existing file, or "-" for stdout (optional)`)
+	flag.StringVar(&diffFormat, "diff-format", "text", `format of the diff: "text", "json" or "markdown"`)
+	flag.BoolVar(&force, "force", false, "overwrite the existing file even if releases are removed from the index")
+	flag.BoolVar(&check, "check", false, "check that the existing file is up to date, instead of overwriting it")
+	flag.StringVar(&consts, "consts", "", "out put file path of the constants of each release (optional)")
+	flag.StringVar(&constsPkg, "consts-pkg", "gocmd", "package name of the constants")
+	flag.StringVar(&constsType, "consts-type", "Version", "type of the constants")
+	flag.StringVar(&src, "src", "", "local JSON file of the release index, read without the network (optional)")
 }
 
 func main() {
@@ -27,36 +49,141 @@ func main() {
 	if dst == "" {
 		log.Fatal("output file path not specified")
 	}
-	if pkg == "" {
-		log.Fatal("output package name not specified")
+	switch outFormat {
+	case "go":
+		if pkg == "" {
+			log.Fatal("output package name not specified")
+		}
+		if varName == "" {
+			log.Fatal("output variable name not specified")
+		}
+	case "json":
+	default:
+		log.Fatalf("unknown format: %s", outFormat)
 	}
-	if varName == "" {
-		log.Fatal("output variable name not specified")
+	if src != "" && index != "" {
+		log.Fatal("-src and -index are exclusive")
 	}
 
-	_, err := internal.FetchOnce()
+	fetched, err := readIndex()
 	if err != nil {
 		log.Fatal(err)
 	}
+	list := releases(fetched)
 
-	type versionStable struct {
-		version string
-		stable  bool
+	old, err := readExisting(dst, outFormat)
+	if err != nil {
+		log.Fatal(err)
 	}
-	var list []versionStable
-	internal.Versions(func(versions map[string]bool) {
-		list = make([]versionStable, 0, len(versions))
-		for v, s := range versions {
-			list = append(list, versionStable{
-				version: v,
-				stable:  s,
-			})
+	d := diff(old, list)
+	if diffOut != "" {
+		err = writeDiff(d)
+		if err != nil {
+			log.Fatal(err)
 		}
-	})
+	}
+	var constsOut []byte
+	if consts != "" {
+		constsOut, err = generateConsts(list)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+	if check {
+		err = checkFile(d, list)
+		if err == nil && consts != "" {
+			err = checkConsts(constsOut)
+		}
+		if err != nil {
+			_, _ = fmt.Fprint(os.Stderr, err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(d.Removed) > 0 && !force {
+		log.Fatalf("%d releases are removed from the index, such as %s: use -force to overwrite %s", len(d.Removed), d.Removed[0].Version, dst)
+	}
+	if consts != "" {
+		err = os.WriteFile(consts, constsOut, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var out []byte
+	if outFormat == "json" {
+		out, err = generateJSON(old, list)
+	} else {
+		out, err = generateGo(list)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if out == nil {
+		// nothing changed
+		return
+	}
+
+	err = os.WriteFile(dst, out, 0644)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func readIndex() ([]internal.Release, error) {
+	if src != "" {
+		data, err := os.ReadFile(src)
+		if err != nil {
+			return nil, err
+		}
+		var list []internal.Release
+		err = json.Unmarshal(data, &list)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", src, err)
+		}
+		return list, nil
+	}
+
+	// always generate from the latest index
+	c := internal.NewCatalog()
+	c.SetCache("off", 0)
+	err := c.SetSource(index)
+	if err != nil {
+		return nil, err
+	}
+	return c.FetchIndex(context.Background())
+}
+
+// releases removes the duplicated releases in the index, and sorts them by version.
+func releases(fetched []internal.Release) []internal.Release {
+	seen := make(map[string]bool, len(fetched))
+	list := make([]internal.Release, 0, len(fetched))
+	for _, r := range fetched {
+		if seen[r.Version] {
+			continue
+		}
+		seen[r.Version] = true
+		list = append(list, r)
+	}
 	sort.Slice(list, func(i, j int) bool {
-		return list[i].version < list[j].version
+		return list[i].Version < list[j].Version
 	})
+	return list
+}
 
+func writeDiff(d *Diff) error {
+	out, err := formatDiff(d, diffFormat)
+	if err != nil {
+		return err
+	}
+	if diffOut == "-" {
+		_, err = os.Stdout.Write(out)
+		return err
+	}
+	return os.WriteFile(diffOut, out, 0644)
+}
+
+func generateGo(list []internal.Release) ([]byte, error) {
 	buf := bytes.NewBuffer(nil)
 	_, _ = fmt.Fprintf(buf, `// Code generated by genvers. DO NOT EDIT.
 package %s
@@ -66,18 +193,72 @@ var %s = map[string]bool{
 
 	for _, item := range list {
 		_, _ = fmt.Fprintf(buf, `%#v: %t,
-`, item.version, item.stable)
+`, item.Version, item.Stable)
 	}
 
 	buf.WriteByte('}')
 
-	formatted, err := format.Source(buf.Bytes())
-	if err != nil {
-		log.Fatal(err)
+	if filesVarName != "" {
+		_, _ = fmt.Fprintf(buf, `
+
+var %s = map[string][]File{
+`, filesVarName)
+		for _, item := range list {
+			if len(item.Files) == 0 {
+				continue
+			}
+			_, _ = fmt.Fprintf(buf, `%#v: {
+`, item.Version)
+			for _, f := range item.Files {
+				_, _ = fmt.Fprintf(buf, `{Filename: %#v, OS: %#v, Arch: %#v, Version: %#v, SHA256: %#v, Size: %d, Kind: %#v},
+`, f.Filename, f.OS, f.Arch, f.Version, f.SHA256, f.Size, f.Kind)
+			}
+			buf.WriteString("},\n")
+		}
+		buf.WriteByte('}')
+	}
+
+	return format.Source(buf.Bytes())
+}
+
+// generateJSON returns the snapshot with the generation timestamp.
+// If the releases are the same as the existing snapshot, it returns nil, so that the timestamp is kept.
+// The timestamp can be fixed by SOURCE_DATE_EPOCH for reproducible builds.
+func generateJSON(old, list []internal.Release) ([]byte, error) {
+	if old != nil && reflect.DeepEqual(normalize(old), normalize(list)) {
+		return nil, nil
+	}
+
+	generatedAt := time.Now().UTC()
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		sec, err := strconv.ParseInt(epoch, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SOURCE_DATE_EPOCH: %w", err)
+		}
+		generatedAt = time.Unix(sec, 0).UTC()
 	}
+	return marshalSnapshot(generatedAt, list)
+}
 
-	err = os.WriteFile(dst, formatted, 0644)
+func marshalSnapshot(generatedAt time.Time, list []internal.Release) ([]byte, error) {
+	out, err := json.MarshalIndent(internal.Snapshot{
+		GeneratedAt: generatedAt.Truncate(time.Second),
+		Releases:    list,
+	}, "", "\t")
 	if err != nil {
-		log.Fatal(err)
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// normalize treats nil and empty files as the same.
+func normalize(list []internal.Release) []internal.Release {
+	n := make([]internal.Release, len(list))
+	for i, r := range list {
+		if len(r.Files) == 0 {
+			r.Files = nil
+		}
+		n[i] = r
 	}
+	return n
 }
@@ -1,8 +1,8 @@
 module github.com/daichitakahashi/gocmd
 
-go 1.19
+go 1.23.0
 
 require (
 	github.com/google/go-cmp v0.5.9
-	golang.org/x/mod v0.7.0
+	golang.org/x/mod v0.25.0
 )
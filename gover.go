@@ -0,0 +1,105 @@
+package gocmd
+
+import (
+	"fmt"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// Version is a Go release version, such as "go1.19", "go1.21.0" or "go1.22rc1".
+// Use ParseVersion to get a well-formed Version from an arbitrary string.
+type Version string
+
+// Kind represents whether a Version is a beta, a release candidate or a release.
+// Kinds are ordered, so that a beta precedes a release candidate and a release candidate precedes a release.
+type Kind uint8
+
+const (
+	KindBeta Kind = iota + 1
+	KindRC
+	KindRelease
+)
+
+// String returns "beta", "rc" or "release".
+func (k Kind) String() string {
+	switch k {
+	case KindBeta:
+		return "beta"
+	case KindRC:
+		return "rc"
+	case KindRelease:
+		return "release"
+	}
+	return ""
+}
+
+// ParseVersion parses s as a Go version.
+// It accepts every naming form used by Go releases: "go1", "go1.N", "go1.N.P", "go1.NbetaK", "go1.NrcK" and "go1.N.PrcK".
+// Whether the version was actually released is not checked; use ValidVersion for that.
+func ParseVersion(s string) (Version, error) {
+	if _, ok := internal.ParseGoVersion(s); !ok {
+		return "", fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	return Version(s), nil
+}
+
+// String returns the version as it was parsed, such as "go1.21.0".
+func (v Version) String() string {
+	return string(v)
+}
+
+// Compare returns -1, 0 or +1 depending on whether v < w, v == w or v > w.
+// Within the same family, betas precede release candidates, release candidates precede the first release,
+// and the first release precedes patch releases.
+// The first release written without patch number ("go1.20") is equal to the one written with ".0" ("go1.20.0").
+// A malformed version is less than any well-formed version.
+func (v Version) Compare(w Version) int {
+	return internal.CompareGoVersions(string(v), string(w))
+}
+
+// Family returns the release family of the version, such as "go1.21" for "go1.21.5" or "go1.21rc2".
+// The family of "go1" is "go1" itself.
+// If the version is malformed, returned value is an empty string.
+func (v Version) Family() Version {
+	c, ok := internal.ParseGoVersion(string(v))
+	if !ok {
+		return ""
+	}
+	if c.Minor == 0 && !c.HasMinor {
+		return Version(fmt.Sprintf("go%d", c.Major))
+	}
+	return Version(fmt.Sprintf("go%d.%d", c.Major, c.Minor))
+}
+
+// Major returns the major version number, 1 for every Go release so far.
+func (v Version) Major() int {
+	c, _ := internal.ParseGoVersion(string(v))
+	return c.Major
+}
+
+// Minor returns the minor version number, such as 21 for "go1.21.5".
+func (v Version) Minor() int {
+	c, _ := internal.ParseGoVersion(string(v))
+	return c.Minor
+}
+
+// Patch returns the patch number, such as 5 for "go1.21.5".
+// It returns 0 for the first release and prereleases of the family.
+func (v Version) Patch() int {
+	c, _ := internal.ParseGoVersion(string(v))
+	return c.Patch
+}
+
+// Kind returns whether the version is a beta, a release candidate or a release.
+// If the version is malformed, returned value is zero.
+func (v Version) Kind() Kind {
+	c, _ := internal.ParseGoVersion(string(v))
+	return Kind(c.Kind)
+}
+
+// Prerelease returns the number of the beta or the release candidate, such as 2 for "go1.22rc2".
+// It returns 0 for a release.
+func (v Version) Prerelease() int {
+	c, _ := internal.ParseGoVersion(string(v))
+	return c.Pre
+}
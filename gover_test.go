@@ -0,0 +1,117 @@
+package gocmd
+
+import (
+	"errors"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseVersion(t *testing.T) {
+	t.Parallel()
+
+	for _, i := range []struct {
+		version    string
+		family     Version
+		minor      int
+		patch      int
+		kind       Kind
+		prerelease int
+	}{
+		{version: "go1", family: "go1", minor: 0, patch: 0, kind: KindRelease},
+		{version: "go1.2", family: "go1.2", minor: 2, patch: 0, kind: KindRelease},
+		{version: "go1.18.5", family: "go1.18", minor: 18, patch: 5, kind: KindRelease},
+		{version: "go1.21.0", family: "go1.21", minor: 21, patch: 0, kind: KindRelease},
+		{version: "go1.19beta1", family: "go1.19", minor: 19, patch: 0, kind: KindBeta, prerelease: 1},
+		{version: "go1.22rc2", family: "go1.22", minor: 22, patch: 0, kind: KindRC, prerelease: 2},
+		{version: "go1.9.2rc2", family: "go1.9", minor: 9, patch: 2, kind: KindRC, prerelease: 2},
+	} {
+		v, err := ParseVersion(i.version)
+		if err != nil {
+			t.Fatalf("%s: %s", i.version, err)
+		}
+		if v.String() != i.version {
+			t.Errorf("%s: unexpected string %q", i.version, v.String())
+		}
+		if v.Family() != i.family {
+			t.Errorf("%s: unexpected family %q", i.version, v.Family())
+		}
+		if v.Major() != 1 || v.Minor() != i.minor || v.Patch() != i.patch {
+			t.Errorf("%s: unexpected components %d.%d.%d", i.version, v.Major(), v.Minor(), v.Patch())
+		}
+		if v.Kind() != i.kind || v.Prerelease() != i.prerelease {
+			t.Errorf("%s: unexpected prerelease %s%d", i.version, v.Kind(), v.Prerelease())
+		}
+	}
+
+	for _, s := range []string{
+		"", "go", "1.19", "go1.", "go1.19.", "go1.019", "go1.19x", "golang1.20", "go1.19beta", "go1.19rc0", "go1.19alpha1", "../go1.19",
+	} {
+		_, err := ParseVersion(s)
+		if !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("%q: expected error %v, got %v", s, ErrInvalidVersion, err)
+		}
+	}
+}
+
+func TestParseVersion_catalog(t *testing.T) {
+	t.Parallel()
+
+	defaultResolver.catalog.Versions(func(versions map[string]bool) {
+		for v, stable := range versions {
+			parsed, err := ParseVersion(v)
+			if err != nil {
+				t.Errorf("%s: %s", v, err)
+				continue
+			}
+			if stable != (parsed.Kind() == KindRelease) {
+				t.Errorf("%s: unexpected kind %s", v, parsed.Kind())
+			}
+		}
+	})
+}
+
+func TestVersion_Compare(t *testing.T) {
+	t.Parallel()
+
+	ordered := []Version{
+		"go1",
+		"go1.2",
+		"go1.2.2",
+		"go1.9beta1",
+		"go1.9rc2",
+		"go1.9",
+		"go1.9.1",
+		"go1.9.2rc2",
+		"go1.9.2",
+		"go1.10beta2",
+		"go1.10",
+		"go1.20rc3",
+		"go1.20",
+		"go1.20.14",
+		"go1.21rc2",
+		"go1.21.0",
+		"go1.21.1",
+		"go1.22rc1",
+	}
+
+	shuffled := append([]Version(nil), ordered...)
+	rand.New(rand.NewSource(1)).Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+	sort.Slice(shuffled, func(i, j int) bool {
+		return shuffled[i].Compare(shuffled[j]) < 0
+	})
+	if diff := cmp.Diff(ordered, shuffled); diff != "" {
+		t.Fatal(diff)
+	}
+
+	if c := Version("go1.20").Compare("go1.20.0"); c != 0 {
+		t.Errorf("go1.20 and go1.20.0 expected to be equal, got %d", c)
+	}
+	if c := Version("unknown").Compare("go1"); c != -1 {
+		t.Errorf("malformed version expected to be less, got %d", c)
+	}
+}
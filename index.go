@@ -0,0 +1,127 @@
+package gocmd
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+const (
+	// DefaultReleaseIndex is the release index fetched when no source is configured.
+	DefaultReleaseIndex = internal.DefaultSource
+
+	// ReleaseIndexEnv is the environment variable to configure the source of the release index,
+	// which is used when SetReleaseIndex is not called.
+	ReleaseIndexEnv = internal.SourceEnv
+)
+
+// SetReleaseIndex sets the source of the release index, which ValidVersion, StableVersion, LookupLatest and so on
+// fetch when the given version is not in the embedded list.
+// The source is one of the following.
+//   - An HTTP(S) URL of the JSON, such as DefaultReleaseIndex.
+//     If the path ends with "/", it is regarded as a mirror of https://go.dev/dl/ and "?mode=json&include=all" is added.
+//   - A file:// URL or a path of the local JSON file.
+//   - A directory containing "releases.json".
+//
+// Empty source resets it to the value of ReleaseIndexEnv, or DefaultReleaseIndex.
+// After the source is changed, the release index is fetched again when it is required.
+// The fetched index is merged into the known versions, unless it is rejected with IndexRejectedError.
+func SetReleaseIndex(source string) error {
+	return defaultResolver.SetReleaseIndex(source)
+}
+
+// SetReleaseIndex sets the source of the release index which r fetches, without affecting other Resolvers.
+func (r *Resolver) SetReleaseIndex(source string) error {
+	return r.catalog.SetSource(source)
+}
+
+// ReleaseIndex returns the source of the release index currently used.
+func ReleaseIndex() string {
+	return defaultResolver.ReleaseIndex()
+}
+
+// ReleaseIndex returns the source of the release index which r fetches.
+func (r *Resolver) ReleaseIndex() string {
+	return r.catalog.Source()
+}
+
+// Fetcher fetches the release index, instead of the source set by SetReleaseIndex.
+// It is useful to serve a canned index in tests, or to get the index in a custom way.
+// Fetch must not call functions of this package, because they may wait for the fetch in progress.
+type Fetcher interface {
+	Fetch(ctx context.Context) ([]Release, error)
+}
+
+// FetcherFunc is an adapter to use an ordinary function as Fetcher.
+type FetcherFunc func(ctx context.Context) ([]Release, error)
+
+// Fetch calls f(ctx).
+func (f FetcherFunc) Fetch(ctx context.Context) ([]Release, error) {
+	return f(ctx)
+}
+
+type fetcher struct {
+	f Fetcher
+}
+
+func (f fetcher) Fetch(ctx context.Context) ([]internal.Release, error) {
+	releases, err := f.f.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	list := make([]internal.Release, 0, len(releases))
+	for _, r := range releases {
+		ir := internal.Release{
+			Version: string(r.Version),
+			Stable:  r.Stable,
+		}
+		for _, f := range r.Files {
+			ir.Files = append(ir.Files, internal.File{
+				Filename: f.Filename,
+				OS:       f.OS,
+				Arch:     f.Arch,
+				Version:  string(r.Version),
+				SHA256:   f.SHA256,
+				Size:     f.Size,
+				Kind:     string(f.Kind),
+			})
+		}
+		list = append(list, ir)
+	}
+	return list, nil
+}
+
+// SetFetcher sets the fetcher of the release index.
+// Nil fetcher resets it to fetch from the source set by SetReleaseIndex.
+// After the fetcher is changed, the release index is fetched again when it is required.
+func SetFetcher(f Fetcher) {
+	defaultResolver.SetFetcher(f)
+}
+
+// SetFetcher sets the fetcher of the release index of r. Nil f resets it to fetch from the source of r.
+func (r *Resolver) SetFetcher(f Fetcher) {
+	if f == nil {
+		r.catalog.SetFetcher(nil)
+		return
+	}
+	r.catalog.SetFetcher(fetcher{f: f})
+}
+
+// SetHTTPClient sets the client to fetch the release index from HTTP(S) source, and to download archives by Install.
+// It allows to use a proxy with custom authentication, or TLS root certificates.
+// Nil client resets it to http.DefaultClient.
+func SetHTTPClient(c *http.Client) {
+	defaultResolver.SetHTTPClient(c)
+}
+
+// SetHTTPClient sets the client which r fetches the release index and downloads archives with.
+func (r *Resolver) SetHTTPClient(c *http.Client) {
+	r.catalog.SetHTTPClient(c)
+}
+
+// IndexRejectedError is returned when the fetched release index looks broken, such as an empty array,
+// a truncated body, or an error page of a proxy that happens to be valid JSON.
+// The index is rejected if it is larger than 64MiB, suspiciously small, or drops known releases.
+// Then, the known versions are kept as they are.
+type IndexRejectedError = internal.RejectedError
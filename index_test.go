@@ -0,0 +1,373 @@
+package gocmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+)
+
+// futureVersion returns a well-formed version newer than any known release.
+func futureVersion(n int) string {
+	newest := Releases()[0].Version
+	return fmt.Sprintf("go%d.%d.0", newest.Major(), newest.Minor()+n)
+}
+
+// writeReleaseIndex writes the index which has the releases in the snapshot and the given stable versions.
+func writeReleaseIndex(t *testing.T, path string, versions ...string) {
+	t.Helper()
+
+	type release struct {
+		Version string `json:"version"`
+		Stable  bool   `json:"stable"`
+	}
+	var list []release
+	for _, v := range versions {
+		list = append(list, release{Version: v, Stable: true})
+	}
+	for _, r := range Releases() {
+		list = append(list, release{
+			Version: string(r.Version),
+			Stable:  r.Stable,
+		})
+	}
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(path, data, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newIndexResolver returns the Resolver which fetches the release index from source, with its own cache directory.
+func newIndexResolver(t *testing.T, source string, opts ...ResolverOption) *Resolver {
+	t.Helper()
+
+	r := NewResolver(opts...)
+	err := r.SetReleaseIndex(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetIndexCache(t.TempDir(), 0)
+	return r
+}
+
+// newFetcherResolver returns the Resolver which fetches the release index with fn.
+func newFetcherResolver(fn func(ctx context.Context) ([]Release, error)) *Resolver {
+	r := NewResolver()
+	r.SetFetcher(FetcherFunc(fn))
+	return r
+}
+
+func TestSetReleaseIndex(t *testing.T) {
+	t.Run("mirror", func(t *testing.T) {
+		version := futureVersion(1)
+		index := filepath.Join(t.TempDir(), "index.json")
+		writeReleaseIndex(t, index, version)
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/dl/" || r.URL.RawQuery != "mode=json&include=all" {
+				http.NotFound(w, r)
+				return
+			}
+			http.ServeFile(w, r, index)
+		}))
+		t.Cleanup(srv.Close)
+		r := newIndexResolver(t, srv.URL+"/dl/")
+
+		err := r.ValidVersion(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		version := futureVersion(1)
+		dir := t.TempDir()
+		writeReleaseIndex(t, filepath.Join(dir, "releases.json"), version)
+		r := newIndexResolver(t, dir)
+
+		stable, err := r.StableVersion(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !stable {
+			t.Fatalf("%s must be stable", version)
+		}
+	})
+
+	t.Run("file URL", func(t *testing.T) {
+		version := futureVersion(1)
+		index := filepath.Join(t.TempDir(), "index.json")
+		writeReleaseIndex(t, index, version)
+		r := newIndexResolver(t, (&url.URL{Scheme: "file", Path: filepath.ToSlash(index)}).String())
+
+		err := r.ValidVersion(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		version := futureVersion(1)
+		dir := t.TempDir()
+		writeReleaseIndex(t, filepath.Join(dir, "releases.json"), version)
+		t.Setenv(ReleaseIndexEnv, dir)
+		r := newIndexResolver(t, "")
+		if got := r.ReleaseIndex(); got != dir {
+			t.Fatalf("unexpected source: %s", got)
+		}
+
+		err := r.ValidVersion(version)
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		err := NewResolver().SetReleaseIndex("ftp://example.com/releases.json")
+		if err == nil {
+			t.Fatal("error expected")
+		}
+	})
+}
+
+func TestValidVersionContext_canceled(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.ValidVersionContext(ctx, futureVersion(2))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetFetcher(t *testing.T) {
+	version := futureVersion(1)
+	releases := append(Releases(), Release{
+		Version: Version(version),
+		Stable:  true,
+		Files: []File{
+			{Filename: version + ".linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: FileKindArchive, Size: 1, SHA256: "00"},
+		},
+	})
+	var called int
+	r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+		called++
+		return releases, nil
+	})
+
+	rel, err := r.LookupRelease(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := rel.File("linux", "amd64", FileKindArchive); !ok {
+		t.Fatalf("file not found: %#v", rel)
+	}
+	if called != 1 {
+		t.Fatalf("unexpected number of calls: %d", called)
+	}
+}
+
+func TestSetFetcher_concurrent(t *testing.T) {
+	version := futureVersion(1)
+	releases := append(Releases(), Release{Version: Version(version), Stable: true})
+	var called atomic.Int32
+	started := make(chan struct{})
+	release := make(chan struct{})
+	r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+		called.Add(1)
+		close(started)
+		<-release
+		return releases, nil
+	})
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- r.ValidVersion(version)
+	}()
+	<-started
+
+	// the known versions are not locked while fetching
+	if len(r.Releases()) == 0 {
+		t.Fatal("no release")
+	}
+	// a waiter gives up by its context
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := r.ValidVersionContext(ctx, version)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	close(release)
+	err = <-errc
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.ValidVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := called.Load(); n != 1 {
+		t.Fatalf("unexpected number of calls: %d", n)
+	}
+}
+
+func TestSetHTTPClient(t *testing.T) {
+	version := futureVersion(1)
+	index := filepath.Join(t.TempDir(), "index.json")
+	writeReleaseIndex(t, index, version)
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, index)
+	}))
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+
+	// the certificate of the server is not trusted by the default client
+	err := r.ValidVersion(version)
+	if err == nil {
+		t.Fatal("error expected")
+	}
+
+	r.SetHTTPClient(srv.Client())
+	err = r.ValidVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIndexRejectedError(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+			return []Release{}, nil
+		})
+
+		err := r.ValidVersion(futureVersion(2))
+		var e *IndexRejectedError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// known versions are kept
+		err = r.ValidVersion("go1.21.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("dropped", func(t *testing.T) {
+		var releases []Release
+		for _, r := range Releases() {
+			if r.Version != "go1.21.0" {
+				releases = append(releases, r)
+			}
+		}
+		releases = append(releases, Release{Version: Version(futureVersion(1)), Stable: true})
+		r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+			return releases, nil
+		})
+
+		err := r.ValidVersion(string(releases[len(releases)-1].Version))
+		var e *IndexRejectedError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("error page", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`[{"version": "maintenance"}]`))
+		}))
+		t.Cleanup(srv.Close)
+		r := newIndexResolver(t, srv.URL+"/dl/")
+
+		err := r.ValidVersion(futureVersion(2))
+		var e *IndexRejectedError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// rejected index is not cached
+		entries, err := r.IndexCacheEntries()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 0 {
+			t.Fatalf("unexpected entries: %#v", entries)
+		}
+	})
+}
+
+func TestValidVersion_noFetch(t *testing.T) {
+	releases := Releases()
+	var called int
+	r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+		called++
+		return releases, nil
+	})
+
+	// malformed, or older than the newest known release of its family
+	for _, v := range []string{"go1.19x", "golang1.20", "go1.18beta9", "../invalid"} {
+		err := r.ValidVersion(v)
+		if !errors.Is(err, ErrInvalidVersion) {
+			t.Fatalf("%s: unexpected error: %v", v, err)
+		}
+	}
+	if called != 0 {
+		t.Fatalf("unexpected fetch: %d", called)
+	}
+
+	// newer than the newest known release, and cached negatively
+	unknown := futureVersion(1)
+	for i := 0; i < 2; i++ {
+		_, err := r.StableVersion(unknown)
+		if !errors.Is(err, ErrInvalidVersion) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if called != 1 {
+		t.Fatalf("unexpected number of fetches: %d", called)
+	}
+}
+
+func TestValidVersion_previousFamily(t *testing.T) {
+	// such as go1.22.10, released with go1.23.4 when the newest known release is go1.23.3
+	newest := Releases()[0].Version
+	var patch Version
+	for _, r := range Releases() {
+		if r.Version.Family() != newest.Family() && r.Version.Kind() == KindRelease {
+			patch = Version(fmt.Sprintf("go%d.%d.%d", r.Version.Major(), r.Version.Minor(), r.Version.Patch()+1))
+			break
+		}
+	}
+	releases := append(Releases(), Release{Version: patch, Stable: true})
+	var called int
+	r := newFetcherResolver(func(ctx context.Context) ([]Release, error) {
+		called++
+		return releases, nil
+	})
+
+	err := r.ValidVersion(string(patch))
+	if err != nil {
+		t.Fatalf("%s: %s", patch, err)
+	}
+	if called != 1 {
+		t.Fatalf("unexpected number of fetches: %d", called)
+	}
+}
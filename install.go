@@ -0,0 +1,320 @@
+package gocmd
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultDownloadURL is the default base URL to download release archives.
+const DefaultDownloadURL = "https://go.dev/dl/"
+
+var (
+	ErrNoArchive        = errors.New("no archive for the platform")
+	ErrChecksumMismatch = errors.New("checksum mismatch")
+)
+
+type installConfig struct {
+	baseURL  string
+	goos     string
+	goarch   string
+	progress func(downloaded, total int64)
+}
+
+// InstallOption configures Install.
+type InstallOption func(c *installConfig)
+
+// WithDownloadURL sets the base URL to download archives from, instead of DefaultDownloadURL.
+// The archive is downloaded from the URL joined with its file name, such as "{baseURL}/go1.21.5.linux-amd64.tar.gz".
+func WithDownloadURL(baseURL string) InstallOption {
+	return func(c *installConfig) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithPlatform sets GOOS and GOARCH of the toolchain to install, instead of runtime.GOOS and runtime.GOARCH.
+func WithPlatform(goos, goarch string) InstallOption {
+	return func(c *installConfig) {
+		c.goos = goos
+		c.goarch = goarch
+	}
+}
+
+// WithProgress sets the callback to report the progress of the download.
+// total is the size of the archive.
+func WithProgress(fn func(downloaded, total int64)) InstallOption {
+	return func(c *installConfig) {
+		c.progress = fn
+	}
+}
+
+// Install downloads the archive of the given version, and extracts it into {dir}/{version}, like golang.org/dl does into ~/sdk.
+// The archive for the platform is chosen from the release metadata, and its SHA256 checksum is verified before extraction.
+// The toolchain is extracted into a temporary directory and renamed at last, so that incomplete installation is never seen.
+// If the toolchain is already installed, it does nothing.
+// In offline mode, it returns ErrOffline instead of downloading the archive.
+//
+// It returns the path of "go" executable of the installed toolchain.
+// The toolchain is registered, so that Lookup, LookupLatest and Determine in this process find it.
+func Install(ctx context.Context, version, dir string, opts ...InstallOption) (string, error) {
+	return defaultResolver.Install(ctx, version, dir, opts...)
+}
+
+// Install downloads and extracts the toolchain like the package-level Install, with the catalog and the HTTP client of r.
+// The installed toolchain is registered only in r.
+func (r *Resolver) Install(ctx context.Context, version, dir string, opts ...InstallOption) (string, error) {
+	c := installConfig{
+		baseURL: DefaultDownloadURL,
+		goos:    runtime.GOOS,
+		goarch:  runtime.GOARCH,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if goBin, ok := installedPath(version, dir, c.goos); ok {
+		r.registerInstalled(version, goBin)
+		return goBin, nil
+	}
+	rel, err := r.LookupReleaseContext(ctx, version)
+	if err != nil {
+		return "", err
+	}
+	if len(rel.Files) == 0 {
+		// the embedded list may not have file metadata
+		fetched, err := r.fetchOnce(ctx)
+		if err != nil {
+			return "", err
+		}
+		if fetched {
+			rel, err = r.LookupReleaseContext(ctx, version)
+			if err != nil {
+				return "", err
+			}
+		}
+	}
+	f, ok := rel.File(c.goos, c.goarch, FileKindArchive)
+	if !ok {
+		return "", fmt.Errorf("%w: %s %s/%s", ErrNoArchive, version, c.goos, c.goarch)
+	}
+	return r.install(ctx, version, f, dir, &c)
+}
+
+func (r *Resolver) install(ctx context.Context, version string, f File, dir string, c *installConfig) (string, error) {
+	goBin, ok := installedPath(version, dir, c.goos)
+	if ok {
+		r.registerInstalled(version, goBin)
+		return goBin, nil
+	}
+
+	if r.catalog.Offline() {
+		return "", fmt.Errorf("%w: cannot download %s", ErrOffline, f.Filename)
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return "", err
+	}
+	archive, err := os.CreateTemp(dir, "."+f.Filename+".*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = archive.Close()
+		_ = os.Remove(archive.Name())
+	}()
+	err = r.download(ctx, strings.TrimSuffix(c.baseURL, "/")+"/"+f.Filename, archive, f, c.progress)
+	if err != nil {
+		return "", err
+	}
+
+	tmp, err := os.MkdirTemp(dir, "."+version+".*")
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = os.RemoveAll(tmp)
+	}()
+	if strings.HasSuffix(f.Filename, ".zip") {
+		err = unzip(archive, f.Size, tmp)
+	} else {
+		_, err = archive.Seek(0, io.SeekStart)
+		if err == nil {
+			err = untar(archive, tmp)
+		}
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to extract %s: %w", f.Filename, err)
+	}
+
+	// archives have the top directory "go"
+	err = os.Rename(filepath.Join(tmp, "go"), filepath.Join(dir, version))
+	if err != nil {
+		return "", err
+	}
+	r.registerInstalled(version, goBin)
+	return goBin, nil
+}
+
+// installedPath returns the path of "go" executable in {dir}/{version}, and reports whether it exists.
+func installedPath(version, dir, goos string) (string, bool) {
+	goBin := filepath.Join(dir, version, "bin", "go")
+	if goos == "windows" {
+		goBin += ".exe"
+	}
+	_, err := os.Stat(goBin)
+	return goBin, err == nil
+}
+
+func (r *Resolver) download(ctx context.Context, url string, w io.Writer, f File, progress func(downloaded, total int64)) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := r.catalog.HTTPClient().Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to download %s: %s", url, resp.Status)
+	}
+
+	h := sha256.New()
+	var downloaded int64
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		if n > 0 {
+			if _, err := w.Write(buf[:n]); err != nil {
+				return err
+			}
+			_, _ = h.Write(buf[:n])
+			downloaded += int64(n)
+			if progress != nil {
+				progress(downloaded, f.Size)
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if f.Size > 0 && downloaded != f.Size {
+		return fmt.Errorf("%w: %s: expected size %d, got %d", ErrChecksumMismatch, f.Filename, f.Size, downloaded)
+	}
+	if sum := hex.EncodeToString(h.Sum(nil)); !strings.EqualFold(sum, f.SHA256) {
+		return fmt.Errorf("%w: %s: expected sha256 %s, got %s", ErrChecksumMismatch, f.Filename, f.SHA256, sum)
+	}
+	return nil
+}
+
+// extractPath returns the path to extract the entry of archive into.
+// It rejects the entry which escapes from dir.
+func extractPath(dir, name string) (string, error) {
+	path := filepath.Join(dir, filepath.FromSlash(name))
+	if path != dir && !strings.HasPrefix(path, dir+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid file name in archive: %s", name)
+	}
+	return path, nil
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	w, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, r)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func untar(r io.Reader, dir string) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		path, err := extractPath(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			err = os.MkdirAll(path, 0755)
+		case tar.TypeReg:
+			err = writeFile(path, tr, hdr.FileInfo().Mode())
+		default:
+			// go archives have only directories and regular files
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func unzip(r io.ReaderAt, size int64, dir string) error {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return err
+	}
+	for _, f := range zr.File {
+		path, err := extractPath(dir, f.Name)
+		if err != nil {
+			return err
+		}
+		if f.FileInfo().IsDir() {
+			err = os.MkdirAll(path, 0755)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeFile(path, rc, f.Mode())
+		_ = rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Resolver) registerInstalled(version, path string) {
+	r.installedMu.Lock()
+	defer r.installedMu.Unlock()
+	r.installed[version] = path
+}
@@ -0,0 +1,271 @@
+package gocmd
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func fakeArchive(t *testing.T, goversion string) []byte {
+	t.Helper()
+
+	script, err := os.ReadFile(fakeGo(t, "go", "local", goversion))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(zw)
+	for _, hdr := range []*tar.Header{
+		{Name: "go/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "go/bin/", Typeflag: tar.TypeDir, Mode: 0755},
+		{Name: "go/bin/go", Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(script))},
+	} {
+		err = tw.WriteHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			_, err = tw.Write(script)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if err = tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err = zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func archiveFile(filename string, data []byte) File {
+	sum := sha256.Sum256(data)
+	return File{
+		Filename: filename,
+		OS:       "linux",
+		Arch:     "amd64",
+		Kind:     FileKindArchive,
+		Size:     int64(len(data)),
+		SHA256:   hex.EncodeToString(sum[:]),
+	}
+}
+
+func TestInstall(t *testing.T) {
+	const version = "go1.16.15"
+	r := NewResolver()
+
+	data := fakeArchive(t, version)
+	f := archiveFile(version+".linux-amd64.tar.gz", data)
+	var requested int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested++
+		if r.URL.Path != "/dl/"+f.Filename {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	var downloaded, total int64
+	c := &installConfig{
+		baseURL: srv.URL + "/dl/",
+		goos:    "linux",
+		goarch:  "amd64",
+		progress: func(d, t int64) {
+			downloaded, total = d, t
+		},
+	}
+	path, err := r.install(context.Background(), version, f, dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, version, "bin", "go"); path != want {
+		t.Fatalf("unexpected path: want %s, got %s", want, path)
+	}
+	if downloaded != f.Size || total != f.Size {
+		t.Fatalf("unexpected progress: %d/%d", downloaded, total)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("temporary files remain: %v", entries)
+	}
+
+	// registered
+	found, err := r.Lookup(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != path {
+		t.Fatalf("unexpected lookup result: %s", found)
+	}
+
+	// the version is probed, instead of the base name of the path
+	found, ver, err := r.Determine(version, ModeExact)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != path || ver != version {
+		t.Fatalf("unexpected determined command: %s %s", found, ver)
+	}
+
+	// already installed
+	_, err = r.install(context.Background(), version, f, dir, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if requested != 1 {
+		t.Fatalf("unexpected number of requests: %d", requested)
+	}
+}
+
+func TestInstall_checksumMismatch(t *testing.T) {
+	const version = "go1.16.15"
+
+	data := fakeArchive(t, version)
+	f := archiveFile(version+".linux-amd64.tar.gz", data)
+	f.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+
+	dir := t.TempDir()
+	_, err := NewResolver().install(context.Background(), version, f, dir, &installConfig{
+		baseURL: srv.URL,
+		goos:    "linux",
+		goarch:  "amd64",
+	})
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("files remain: %v", entries)
+	}
+}
+
+func TestInstall_index(t *testing.T) {
+	version := futureVersion(1)
+	broken := futureVersion(2)
+	data := fakeArchive(t, version)
+	files := map[string]File{
+		version: archiveFile(version+".linux-amd64.tar.gz", data),
+		broken:  archiveFile(broken+".linux-amd64.tar.gz", data),
+	}
+	f := files[broken]
+	f.SHA256 = hex.EncodeToString(make([]byte, sha256.Size))
+	files[broken] = f
+
+	type file struct {
+		Filename string `json:"filename"`
+		OS       string `json:"os"`
+		Arch     string `json:"arch"`
+		Version  string `json:"version"`
+		SHA256   string `json:"sha256"`
+		Size     int64  `json:"size"`
+		Kind     string `json:"kind"`
+	}
+	type release struct {
+		Version string `json:"version"`
+		Stable  bool   `json:"stable"`
+		Files   []file `json:"files,omitempty"`
+	}
+	var list []release
+	for _, v := range []string{broken, version} {
+		f := files[v]
+		list = append(list, release{
+			Version: v,
+			Stable:  true,
+			Files: []file{{
+				Filename: f.Filename,
+				OS:       f.OS,
+				Arch:     f.Arch,
+				Version:  v,
+				SHA256:   f.SHA256,
+				Size:     f.Size,
+				Kind:     string(f.Kind),
+			}},
+		})
+	}
+	for _, r := range Releases() {
+		list = append(list, release{Version: string(r.Version), Stable: r.Stable})
+	}
+	index, err := json.Marshal(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/dl/":
+			_, _ = w.Write(index)
+		case "/dl/" + files[version].Filename, "/dl/" + files[broken].Filename:
+			_, _ = w.Write(data)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	r := NewResolver()
+	err = r.SetReleaseIndex(srv.URL + "/dl/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.SetIndexCache("off", 0)
+	opts := []InstallOption{
+		WithDownloadURL(srv.URL + "/dl/"),
+		WithPlatform("linux", "amd64"),
+	}
+
+	dir := t.TempDir()
+	_, err = r.Install(context.Background(), broken, dir, opts...)
+	if !errors.Is(err, ErrChecksumMismatch) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	path, err := r.Install(context.Background(), version, dir, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, version, "bin", "go"); path != want {
+		t.Fatalf("unexpected path: want %s, got %s", want, path)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("unexpected files: %v", entries)
+	}
+
+	// registered, so that it is found without PATH
+	found, err := r.Lookup(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found != path {
+		t.Fatalf("unexpected lookup result: %s", found)
+	}
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// CacheEnv is the environment variable to configure the directory of the cache of the release index.
+	// "off" disables the cache.
+	CacheEnv = "GOCMD_CACHE"
+
+	// DefaultCacheTTL is the period in which the cached release index is used without any request.
+	DefaultCacheTTL = time.Hour
+
+	cachePattern = "index-*.json"
+)
+
+// CacheEntry is the release index fetched from an HTTP(S) source and stored on disk.
+type CacheEntry struct {
+	Source       string          `json:"source"`
+	ETag         string          `json:"etag,omitempty"`
+	LastModified string          `json:"last_modified,omitempty"`
+	FetchedAt    time.Time       `json:"fetched_at"`
+	Data         json.RawMessage `json:"data"`
+
+	// Path is the path of the cache file.
+	Path string `json:"-"`
+}
+
+// SetCache sets the directory and TTL of the cache of the release index.
+// Empty dir resets the directory to the value of CacheEnv, or "gocmd" in os.UserCacheDir.
+// If dir is "off", the cache is disabled. Non-positive ttl resets it to DefaultCacheTTL.
+func (c *Catalog) SetCache(dir string, ttl time.Duration) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.cacheDir = dir
+	c.cacheTTL = ttl
+}
+
+// CacheDir returns the directory of the cache, or an empty string if the cache is disabled.
+func (c *Catalog) CacheDir() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.currentCacheDir()
+}
+
+func (c *Catalog) currentCacheDir() string {
+	dir := c.cacheDir
+	if dir == "" {
+		dir = os.Getenv(CacheEnv)
+	}
+	if dir == "off" {
+		return ""
+	}
+	if dir == "" {
+		userDir, err := os.UserCacheDir()
+		if err != nil {
+			// no cache is available
+			return ""
+		}
+		dir = filepath.Join(userDir, "gocmd")
+	}
+	return dir
+}
+
+func (c *Catalog) currentCacheTTL() time.Duration {
+	if c.cacheTTL > 0 {
+		return c.cacheTTL
+	}
+	return DefaultCacheTTL
+}
+
+func cachePath(dir, src string) string {
+	sum := sha256.Sum256([]byte(src))
+	return filepath.Join(dir, "index-"+hex.EncodeToString(sum[:8])+".json")
+}
+
+func readCache(path string) (*CacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var e CacheEntry
+	err = json.Unmarshal(data, &e)
+	if err != nil {
+		return nil, err
+	}
+	e.Path = path
+	return &e, nil
+}
+
+// writeCache writes the entry into a temporary file and renames it, so that readers never see a partial file.
+func writeCache(path string, e *CacheEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(path)
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".index-*")
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(f.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(f.Name())
+		return err
+	}
+	e.Path = path
+	return nil
+}
+
+// removeCache removes the cache of the HTTP(S) source.
+func (c config) removeCache() {
+	rawURL, _, err := parseSource(c.source)
+	if err != nil || rawURL == "" || c.cacheDir == "" {
+		return
+	}
+	_ = os.Remove(cachePath(c.cacheDir, rawURL))
+}
+
+// CacheEntries returns the entries in the cache directory.
+func (c *Catalog) CacheEntries() ([]CacheEntry, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	dir := c.currentCacheDir()
+	if dir == "" {
+		return nil, nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, cachePattern))
+	if err != nil {
+		return nil, err
+	}
+	var entries []CacheEntry
+	for _, path := range paths {
+		e, err := readCache(path)
+		if err != nil {
+			// broken file is overwritten by the next fetch
+			continue
+		}
+		entries = append(entries, *e)
+	}
+	return entries, nil
+}
+
+// ClearCache removes the entries in the cache directory.
+func (c *Catalog) ClearCache() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	dir := c.currentCacheDir()
+	if dir == "" {
+		return nil
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, cachePattern))
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, path := range paths {
+		err := os.Remove(path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
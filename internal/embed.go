@@ -0,0 +1,27 @@
+package internal
+
+import (
+	_ "embed"
+	"time"
+)
+
+//go:embed versions.json
+var snapshotData []byte
+
+var snapshot = mustParseSnapshot(snapshotData)
+
+// The maps are shared by every Catalog as the initial versions, because they are never modified.
+var snapshotVersions, snapshotFiles = indexMaps(snapshot.Releases)
+
+func mustParseSnapshot(data []byte) *Snapshot {
+	s, err := ParseSnapshot(data)
+	if err != nil {
+		panic("broken snapshot: " + err.Error())
+	}
+	return s
+}
+
+// SnapshotTime returns the time when the embedded snapshot was generated.
+func SnapshotTime() time.Time {
+	return snapshot.GeneratedAt
+}
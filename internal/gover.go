@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// go1, go1.N, go1.N.P, go1.NbetaK, go1.NrcK, go1.N.PrcK
+var goVersionRe = regexp.MustCompile(`^go([1-9][0-9]*)(?:\.(0|[1-9][0-9]*))?(?:\.(0|[1-9][0-9]*))?(?:(beta|rc)([1-9][0-9]*))?$`)
+
+// Kinds of GoVersion, in the same order as gocmd.Kind.
+const (
+	KindBeta = iota + 1
+	KindRC
+	KindRelease
+)
+
+// GoVersion is the components of a Go version, such as "go1.21.5" or "go1.22rc1".
+// It is shared by gocmd.Version and cmd/genvers, which generates constants into gocmd.
+type GoVersion struct {
+	Major, Minor, Patch int
+
+	// HasMinor reports whether the minor version is written, which is false only for "go1".
+	HasMinor bool
+
+	// Kind is KindBeta, KindRC or KindRelease.
+	Kind int
+
+	// Pre is the number of the beta or the release candidate.
+	Pre int
+}
+
+// ParseGoVersion parses s as a Go version.
+// Numbers with leading zeros are rejected, as they are never used by Go releases.
+func ParseGoVersion(s string) (GoVersion, bool) {
+	m := goVersionRe.FindStringSubmatch(s)
+	if m == nil {
+		return GoVersion{}, false
+	}
+	var v GoVersion
+	var err error
+	if v.Major, err = strconv.Atoi(m[1]); err != nil {
+		return GoVersion{}, false
+	}
+	if m[2] != "" {
+		v.HasMinor = true
+		if v.Minor, err = strconv.Atoi(m[2]); err != nil {
+			return GoVersion{}, false
+		}
+	}
+	if m[3] != "" {
+		if v.Patch, err = strconv.Atoi(m[3]); err != nil {
+			return GoVersion{}, false
+		}
+	}
+	v.Kind = KindRelease
+	if m[4] != "" {
+		v.Kind = KindBeta
+		if m[4] == "rc" {
+			v.Kind = KindRC
+		}
+		if v.Pre, err = strconv.Atoi(m[5]); err != nil {
+			return GoVersion{}, false
+		}
+	}
+	return v, true
+}
+
+// CompareGoVersions returns -1, 0 or +1 depending on whether v < w, v == w or v > w.
+// A malformed version is less than any well-formed version.
+func CompareGoVersions(v, w string) int {
+	vc, vok := ParseGoVersion(v)
+	wc, wok := ParseGoVersion(w)
+	switch {
+	case !vok && !wok:
+		return strings.Compare(v, w)
+	case !vok:
+		return -1
+	case !wok:
+		return 1
+	}
+	for _, d := range [...]int{
+		vc.Major - wc.Major,
+		vc.Minor - wc.Minor,
+		vc.Patch - wc.Patch,
+		vc.Kind - wc.Kind,
+		vc.Pre - wc.Pre,
+	} {
+		if d < 0 {
+			return -1
+		} else if d > 0 {
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"strconv"
+)
+
+// OfflineEnv is the environment variable to enable offline mode, such as GOCMD_OFFLINE=1.
+const OfflineEnv = "GOCMD_OFFLINE"
+
+// ErrOffline is returned by FetchOnce in offline mode, instead of accessing the network.
+var ErrOffline = errors.New("offline mode")
+
+// SetOffline enables or disables offline mode, regardless of OfflineEnv.
+func (c *Catalog) SetOffline(b bool) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.offlineSet = true
+	c.offline = b
+	c.loadedLocal = false
+	c.gen++
+}
+
+// Offline reports whether offline mode is enabled.
+func (c *Catalog) Offline() bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.isOffline()
+}
+
+func (c *Catalog) isOffline() bool {
+	if c.offlineSet {
+		return c.offline
+	}
+	b, _ := strconv.ParseBool(os.Getenv(OfflineEnv))
+	return b
+}
+
+// loadLocal reads the release index without the network, from the local file source,
+// or the cache of HTTP(S) source regardless of its TTL.
+// A custom Fetcher is never called, because it may access the network.
+func loadLocal(c config) ([]Release, error) {
+	if c.fetcher != nil {
+		return nil, ErrOffline
+	}
+	rawURL, path, err := parseSource(c.source)
+	if err != nil {
+		return nil, err
+	}
+	if rawURL == "" {
+		data, err := readFile(path)
+		if err != nil {
+			return nil, err
+		}
+		return parseIndex(data)
+	}
+
+	dir := c.cacheDir
+	if dir == "" {
+		return nil, ErrOffline
+	}
+	e, err := readCache(cachePath(dir, rawURL))
+	if err != nil {
+		return nil, err
+	}
+	if e.Source != rawURL {
+		return nil, ErrOffline
+	}
+	return parseIndex(e.Data)
+}
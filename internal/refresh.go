@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"context"
+	"sort"
+)
+
+// Subscribe registers fn, which is called with the versions newly appeared by FetchOnce or Refresh.
+// It returns the function to unsubscribe.
+// fn is called without the lock, in the goroutine which fetched the release index.
+func (c *Catalog) Subscribe(fn func(added []string)) func() {
+	c.m.Lock()
+	defer c.m.Unlock()
+	id := c.nextSubID
+	c.nextSubID++
+	c.subscribers[id] = fn
+	return func() {
+		c.m.Lock()
+		defer c.m.Unlock()
+		delete(c.subscribers, id)
+	}
+}
+
+// currentSubscribers must be called with c.m held.
+func (c *Catalog) currentSubscribers() []func(added []string) {
+	ids := make([]int, 0, len(c.subscribers))
+	for id := range c.subscribers {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	subs := make([]func(added []string), 0, len(ids))
+	for _, id := range ids {
+		subs = append(subs, c.subscribers[id])
+	}
+	return subs
+}
+
+func notify(subs []func(added []string), added []string) {
+	if len(added) == 0 {
+		return
+	}
+	for _, fn := range subs {
+		fn(added)
+	}
+}
+
+// Refresh fetches the release index again, even if FetchOnce has fetched it, and swaps the versions atomically.
+// The lock is not held while fetching, so that readers are not blocked.
+// The cached index is revalidated regardless of its TTL.
+// It returns the versions newly appeared. In offline mode, it returns ErrOffline.
+func (c *Catalog) Refresh(ctx context.Context) ([]string, error) {
+	c.m.Lock()
+	conf := c.currentConfig()
+	known := c.versions
+	c.m.Unlock()
+	if conf.offline {
+		return nil, ErrOffline
+	}
+	conf.cacheTTL = 0
+
+	v, f, err := fetch(ctx, conf, known)
+	if err != nil {
+		return nil, err
+	}
+
+	c.m.Lock()
+	added := c.swap(v, f)
+	c.fetched = true
+	subs := c.currentSubscribers()
+	c.m.Unlock()
+	notify(subs, added)
+	return added, nil
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// MaxIndexSize is the maximum size of the release index to read.
+// The index of go.dev is a few megabytes, including files of all releases.
+const MaxIndexSize = 64 << 20
+
+// RejectedError is returned when the fetched release index looks broken, and the known versions are kept.
+type RejectedError struct {
+	// Source is the source of the release index, or "fetcher" for a custom Fetcher.
+	Source string
+
+	// Reason describes why the index is rejected.
+	Reason string
+}
+
+func (e *RejectedError) Error() string {
+	return fmt.Sprintf("release index from %s is rejected: %s", e.Source, e.Reason)
+}
+
+// readLimited reads r up to MaxIndexSize.
+func readLimited(src string, r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxIndexSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxIndexSize {
+		return nil, &RejectedError{
+			Source: src,
+			Reason: fmt.Sprintf("larger than %d bytes", MaxIndexSize),
+		}
+	}
+	return data, nil
+}
+
+func readFileLimited(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = f.Close()
+	}()
+	return readLimited(path, f)
+}
+
+// validate rejects the fetched index that is suspiciously small, or drops known versions.
+// An empty array, a truncated body or an error page that happens to be valid JSON is caught here.
+func validate(src string, r []Release, known map[string]bool) error {
+	if len(r) < len(known)/2 {
+		return &RejectedError{
+			Source: src,
+			Reason: fmt.Sprintf("suspiciously small: %d releases while %d releases are known", len(r), len(known)),
+		}
+	}
+	fetched := make(map[string]bool, len(r))
+	for _, rr := range r {
+		if rr.Version == "" {
+			return &RejectedError{
+				Source: src,
+				Reason: "release without version",
+			}
+		}
+		fetched[rr.Version] = true
+	}
+	var dropped []string
+	for v := range known {
+		if !fetched[v] {
+			dropped = append(dropped, v)
+		}
+	}
+	if len(dropped) > 0 {
+		sort.Strings(dropped)
+		return &RejectedError{
+			Source: src,
+			Reason: fmt.Sprintf("%d known releases are dropped, such as %s", len(dropped), dropped[0]),
+		}
+	}
+	return nil
+}
@@ -0,0 +1,22 @@
+package internal
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Snapshot is the release index embedded at build time.
+type Snapshot struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	Releases    []Release `json:"releases"`
+}
+
+// ParseSnapshot parses the snapshot generated by cmd/genvers.
+func ParseSnapshot(data []byte) (*Snapshot, error) {
+	var s Snapshot
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -0,0 +1,21 @@
+package internal
+
+// Unknown reports whether the version is not found in the fetched release index.
+func (c *Catalog) Unknown(version string) bool {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.unknown[version]
+}
+
+// MarkUnknown records that the version is not found in the fetched release index,
+// until the source is changed or the version appears.
+func (c *Catalog) MarkUnknown(version string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.unknown[version] = true
+}
+
+// resetUnknown must be called with c.m held.
+func (c *Catalog) resetUnknown() {
+	c.unknown = map[string]bool{}
+}
@@ -1,54 +1,399 @@
-//go:generate go run ../cmd/genvers -dst=./versions.gen.go -pkg=internal -var=versions
+//go:generate go run ../cmd/genvers -dst=./versions.json -format=json -consts=../versions.gen.go
 package internal
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
-	"io"
+	"fmt"
 	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 )
 
-var (
-	m       sync.Mutex
-	fetched bool
+const (
+	// DefaultSource is the release index used when no source is configured.
+	DefaultSource = "https://go.dev/dl/?mode=json&include=all"
+
+	// SourceEnv is the environment variable to configure the source of the release index.
+	SourceEnv = "GOCMD_RELEASE_INDEX"
+
+	// IndexFilename is the name of the release index file looked up in a directory source.
+	IndexFilename = "releases.json"
 )
 
-type version struct {
+// Catalog is the known releases, and the settings to fetch the release index.
+// It starts from the embedded snapshot.
+type Catalog struct {
+	m        sync.Mutex
+	versions map[string]bool
+	files    map[string][]File
+	fetched  bool
+	source   string
+	fetcher  Fetcher
+	client   *http.Client
+
+	cacheDir string
+	cacheTTL time.Duration
+
+	offlineSet  bool
+	offline     bool
+	loadedLocal bool
+
+	// gen is incremented when the settings to fetch are changed, so that the fetch in flight is not reused.
+	gen      int
+	inflight *fetchCall
+
+	// unknown is the negative cache of the versions not found in the fetched release index.
+	unknown map[string]bool
+
+	subscribers map[int]func(added []string)
+	nextSubID   int
+}
+
+// NewCatalog returns the catalog which knows the releases in the embedded snapshot.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		versions:    snapshotVersions,
+		files:       snapshotFiles,
+		unknown:     map[string]bool{},
+		subscribers: map[int]func(added []string){},
+	}
+}
+
+// Release is a release listed in https://go.dev/dl/?mode=json&include=all.
+type Release struct {
 	Version string `json:"version"`
 	Stable  bool   `json:"stable"`
-	// Files []any `json:"files"`
+	Files   []File `json:"files,omitempty"`
+}
+
+// Fetcher fetches the release index.
+// Fetch is called without the lock of the catalog held, but it must not call methods of the catalog which fetch the release index.
+type Fetcher interface {
+	Fetch(ctx context.Context) ([]Release, error)
+}
+
+// File is a downloadable file of a release, as listed in https://go.dev/dl/?mode=json&include=all.
+type File struct {
+	Filename string `json:"filename"`
+	OS       string `json:"os"`
+	Arch     string `json:"arch"`
+	Version  string `json:"version"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Kind     string `json:"kind"`
 }
 
-func Versions(fn func(versions map[string]bool)) {
-	m.Lock()
-	defer m.Unlock()
-	fn(versions)
+// Versions calls fn with the known versions and whether each of them is stable.
+func (c *Catalog) Versions(fn func(versions map[string]bool)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	fn(c.versions)
 }
 
-func FetchOnce() (bool, error) {
-	m.Lock()
-	defer m.Unlock()
-	if fetched {
+// Releases calls fn with versions and the files of each version.
+func (c *Catalog) Releases(fn func(versions map[string]bool, files map[string][]File)) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	fn(c.versions, c.files)
+}
+
+// FetchOnce fetches the release index from the source, unless it has been fetched already.
+// It reports whether the versions are updated.
+// Like Refresh, the lock is not held while fetching, so that readers are not blocked.
+// Concurrent calls share the fetch in flight, and each of them stops waiting for it when its ctx is done.
+// If new versions appear, subscribers are notified after the lock is released.
+func (c *Catalog) FetchOnce(ctx context.Context) (bool, error) {
+	c.m.Lock()
+	if c.fetched {
+		c.m.Unlock()
 		return false, nil
 	}
-	v, err := fetch()
+	if call := c.inflight; call != nil && call.gen == c.gen {
+		c.m.Unlock()
+		select {
+		case <-call.done:
+			return call.updated, call.err
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	call := &fetchCall{
+		done: make(chan struct{}),
+		gen:  c.gen,
+	}
+	c.inflight = call
+	conf := c.currentConfig()
+	known := c.versions
+	loadedLocal := c.loadedLocal
+	c.m.Unlock()
+
+	v, f, err := fetchOnce(ctx, conf, known, loadedLocal)
+
+	c.m.Lock()
+	var added []string
+	if err == nil {
+		added = c.swap(v, f)
+		call.updated = true
+	}
+	if c.gen == call.gen {
+		// unless the settings are changed while fetching
+		if conf.offline {
+			c.loadedLocal = true
+		} else if err == nil {
+			c.fetched = true
+		}
+	}
+	if c.inflight == call {
+		c.inflight = nil
+	}
+	call.err = err
+	subs := c.currentSubscribers()
+	c.m.Unlock()
+	close(call.done)
+	notify(subs, added)
+	return call.updated, call.err
+}
+
+// fetchCall is the fetch of FetchOnce in flight.
+// updated and err are written before done is closed.
+type fetchCall struct {
+	done    chan struct{}
+	gen     int
+	updated bool
+	err     error
+}
+
+// fetchOnce fetches the release index in the settings of conf.
+// In offline mode, the local index is loaded only once, unless the settings are changed.
+func fetchOnce(ctx context.Context, conf config, known map[string]bool, loadedLocal bool) (map[string]bool, map[string][]File, error) {
+	if conf.offline {
+		if loadedLocal {
+			return nil, nil, ErrOffline
+		}
+		r, err := loadLocal(conf)
+		if err == nil {
+			err = validate(conf.source, r, known)
+		}
+		if err != nil {
+			return nil, nil, ErrOffline
+		}
+		v, f := indexMaps(r)
+		return v, f, nil
+	}
+	return fetch(ctx, conf, known)
+}
+
+// swap merges the fetched versions and files into the known ones, and returns the versions newly appeared.
+// The maps are replaced with new ones instead of being modified, so that the maps once read never change.
+// It must be called with c.m held.
+func (c *Catalog) swap(v map[string]bool, f map[string][]File) []string {
+	merged := make(map[string]bool, len(c.versions)+len(v))
+	for vv, stable := range c.versions {
+		merged[vv] = stable
+	}
+	var added []string
+	for vv, stable := range v {
+		if _, ok := merged[vv]; !ok {
+			added = append(added, vv)
+			delete(c.unknown, vv)
+		}
+		merged[vv] = stable
+	}
+	mergedFiles := make(map[string][]File, len(c.files)+len(f))
+	for vv, ff := range c.files {
+		mergedFiles[vv] = ff
+	}
+	for vv, ff := range f {
+		mergedFiles[vv] = ff
+	}
+	c.versions = merged
+	c.files = mergedFiles
+	return added
+}
+
+// config is the snapshot of the settings to fetch the release index.
+type config struct {
+	source   string
+	fetcher  Fetcher
+	client   *http.Client
+	cacheDir string
+	cacheTTL time.Duration
+	offline  bool
+}
+
+// currentConfig must be called with c.m held.
+func (c *Catalog) currentConfig() config {
+	return config{
+		source:   c.currentSource(),
+		fetcher:  c.fetcher,
+		client:   c.httpClient(),
+		cacheDir: c.currentCacheDir(),
+		cacheTTL: c.currentCacheTTL(),
+		offline:  c.isOffline(),
+	}
+}
+
+// SetSource sets the source of the release index, and lets the next FetchOnce fetch from it.
+// Empty src resets the source to the environment variable SourceEnv, or DefaultSource.
+func (c *Catalog) SetSource(src string) error {
+	if src != "" {
+		if _, _, err := parseSource(src); err != nil {
+			return err
+		}
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.source = src
+	c.fetched = false
+	c.gen++
+	c.loadedLocal = false
+	c.resetUnknown()
+	return nil
+}
+
+// SetFetcher sets the fetcher of the release index, which replaces the source.
+// Nil fetcher resets it to fetch from the source.
+func (c *Catalog) SetFetcher(f Fetcher) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.fetcher = f
+	c.fetched = false
+	c.gen++
+	c.loadedLocal = false
+	c.resetUnknown()
+}
+
+// SetHTTPClient sets the client to fetch the release index from HTTP(S) source.
+// Nil client resets it to http.DefaultClient.
+func (c *Catalog) SetHTTPClient(client *http.Client) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.client = client
+	c.fetched = false
+	c.gen++
+	c.resetUnknown()
+}
+
+// HTTPClient returns the client set by SetHTTPClient, or http.DefaultClient.
+func (c *Catalog) HTTPClient() *http.Client {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.httpClient()
+}
+
+func (c *Catalog) httpClient() *http.Client {
+	if c.client != nil {
+		return c.client
+	}
+	return http.DefaultClient
+}
+
+// Source returns the source of the release index currently used.
+func (c *Catalog) Source() string {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.currentSource()
+}
+
+func (c *Catalog) currentSource() string {
+	if c.source != "" {
+		return c.source
+	}
+	if src := os.Getenv(SourceEnv); src != "" {
+		return src
+	}
+	return DefaultSource
+}
+
+// parseSource returns the URL to fetch, or the path of the local file.
+// An HTTP(S) URL whose path ends with "/" is regarded as a mirror of https://go.dev/dl/,
+// and "?mode=json&include=all" is added.
+// A file:// URL or a path which is a directory is regarded to have IndexFilename in it.
+func parseSource(src string) (rawURL, path string, err error) {
+	u, err := url.Parse(src)
+	if err != nil || len(u.Scheme) <= 1 {
+		// a local path, including Windows paths such as C:\releases.json
+		return "", src, nil
+	}
+	switch u.Scheme {
+	case "http", "https":
+		if strings.HasSuffix(u.Path, "/") && u.RawQuery == "" {
+			u.RawQuery = "mode=json&include=all"
+		}
+		return u.String(), "", nil
+	case "file":
+		return "", filepath.FromSlash(u.Path), nil
+	default:
+		return "", "", fmt.Errorf("unsupported source of release index: %s", src)
+	}
+}
+
+func readSource(ctx context.Context, c config) ([]byte, error) {
+	rawURL, path, err := parseSource(c.source)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	if rawURL != "" {
+		return fetchHTTP(ctx, c, rawURL)
+	}
+
+	return readFile(path)
+}
+
+// readFile reads the local release index. If path is a directory, IndexFilename in it is read.
+func readFile(path string) ([]byte, error) {
+	if fi, err := os.Stat(path); err == nil && fi.IsDir() {
+		path = filepath.Join(path, IndexFilename)
 	}
-	versions = v
-	fetched = true
-	return true, nil
+	return readFileLimited(path)
 }
 
-func fetch() (map[string]bool, error) {
-	var c http.Client
-	resp, err := c.Get("https://go.dev/dl/?mode=json&include=all")
+// fetchHTTP fetches the release index from rawURL through the cache on disk.
+// The cached index is used without any request within the TTL.
+// After that, a conditional request is sent with ETag and Last-Modified of the cached index.
+func fetchHTTP(ctx context.Context, c config, rawURL string) ([]byte, error) {
+	var path string
+	var cached *CacheEntry
+	if dir := c.cacheDir; dir != "" {
+		path = cachePath(dir, rawURL)
+		cached, _ = readCache(path)
+		if cached != nil && cached.Source != rawURL {
+			cached = nil
+		}
+	}
+	if cached != nil && time.Since(cached.FetchedAt) < c.cacheTTL {
+		return cached.Data, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
 	if err != nil {
 		return nil, err
 	}
-	data, err := io.ReadAll(resp.Body)
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := readLimited(rawURL, resp.Body)
 	_ = resp.Body.Close()
+	if resp.StatusCode == http.StatusNotModified && cached != nil {
+		cached.FetchedAt = time.Now()
+		_ = writeCache(path, cached)
+		return cached.Data, nil
+	}
 	if resp.StatusCode != http.StatusOK {
 		return nil, errors.New(http.StatusText(resp.StatusCode))
 	}
@@ -56,15 +401,92 @@ func fetch() (map[string]bool, error) {
 		return nil, err
 	}
 
-	var v []version
-	err = json.Unmarshal(data, &v)
+	if path != "" && json.Valid(data) {
+		// the cache is just an optimization
+		_ = writeCache(path, &CacheEntry{
+			Source:       rawURL,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			FetchedAt:    time.Now(),
+			Data:         data,
+		})
+	}
+	return data, nil
+}
+
+// fetchSource fetches the release index from the source with the client set by SetHTTPClient.
+func fetchSource(ctx context.Context, c config) ([]Release, error) {
+	data, err := readSource(ctx, c)
 	if err != nil {
 		return nil, err
 	}
+	return parseIndex(data)
+}
+
+func parseIndex(data []byte) ([]Release, error) {
+	var v []Release
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// fetch fetches the release index, and validates it against the known versions.
+// If the index is rejected, its cache is removed so that it is fetched again next time.
+func fetch(ctx context.Context, c config, known map[string]bool) (map[string]bool, map[string][]File, error) {
+	v, err := fetchReleases(ctx, c, known)
+	if err != nil {
+		return nil, nil, err
+	}
+	m, f := indexMaps(v)
+	return m, f, nil
+}
+
+func fetchReleases(ctx context.Context, c config, known map[string]bool) ([]Release, error) {
+	var v []Release
+	var err error
+	src := c.source
+	if c.fetcher != nil {
+		src = "fetcher"
+		v, err = c.fetcher.Fetch(ctx)
+	} else {
+		v, err = fetchSource(ctx, c)
+	}
+	if err == nil {
+		err = validate(src, v, known)
+	}
+	if err != nil {
+		var re *RejectedError
+		if errors.As(err, &re) && c.fetcher == nil {
+			c.removeCache()
+		}
+		return nil, err
+	}
+	return v, nil
+}
+
+// FetchIndex fetches the release index as it is, without merging it into the known versions.
+// Unlike FetchOnce, the index which drops known versions is not rejected,
+// so that cmd/genvers can compare it with the existing snapshot.
+func (c *Catalog) FetchIndex(ctx context.Context) ([]Release, error) {
+	c.m.Lock()
+	conf := c.currentConfig()
+	c.m.Unlock()
+	if conf.offline {
+		return nil, ErrOffline
+	}
+	return fetchReleases(ctx, conf, nil)
+}
 
+func indexMaps(v []Release) (map[string]bool, map[string][]File) {
 	m := map[string]bool{}
+	f := map[string][]File{}
 	for _, vv := range v {
 		m[vv.Version] = vv.Stable
+		if len(vv.Files) > 0 {
+			f[vv.Version] = vv.Files
+		}
 	}
-	return m, nil
+	return m, f
 }
@@ -1,306 +0,0 @@
-// Code generated by genvers. DO NOT EDIT.
-package internal
-
-var versions = map[string]bool{
-	"go1":         true,
-	"go1.10":      true,
-	"go1.10.1":    true,
-	"go1.10.2":    true,
-	"go1.10.3":    true,
-	"go1.10.4":    true,
-	"go1.10.5":    true,
-	"go1.10.6":    true,
-	"go1.10.7":    true,
-	"go1.10.8":    true,
-	"go1.10beta1": false,
-	"go1.10beta2": false,
-	"go1.10rc1":   false,
-	"go1.10rc2":   false,
-	"go1.11":      true,
-	"go1.11.1":    true,
-	"go1.11.10":   true,
-	"go1.11.11":   true,
-	"go1.11.12":   true,
-	"go1.11.13":   true,
-	"go1.11.2":    true,
-	"go1.11.3":    true,
-	"go1.11.4":    true,
-	"go1.11.5":    true,
-	"go1.11.6":    true,
-	"go1.11.7":    true,
-	"go1.11.8":    true,
-	"go1.11.9":    true,
-	"go1.11beta1": false,
-	"go1.11beta2": false,
-	"go1.11beta3": false,
-	"go1.11rc1":   false,
-	"go1.11rc2":   false,
-	"go1.12":      true,
-	"go1.12.1":    true,
-	"go1.12.10":   true,
-	"go1.12.11":   true,
-	"go1.12.12":   true,
-	"go1.12.13":   true,
-	"go1.12.14":   true,
-	"go1.12.15":   true,
-	"go1.12.16":   true,
-	"go1.12.17":   true,
-	"go1.12.2":    true,
-	"go1.12.3":    true,
-	"go1.12.4":    true,
-	"go1.12.5":    true,
-	"go1.12.6":    true,
-	"go1.12.7":    true,
-	"go1.12.8":    true,
-	"go1.12.9":    true,
-	"go1.12beta1": false,
-	"go1.12beta2": false,
-	"go1.12rc1":   false,
-	"go1.13":      true,
-	"go1.13.1":    true,
-	"go1.13.10":   true,
-	"go1.13.11":   true,
-	"go1.13.12":   true,
-	"go1.13.13":   true,
-	"go1.13.14":   true,
-	"go1.13.15":   true,
-	"go1.13.2":    true,
-	"go1.13.3":    true,
-	"go1.13.4":    true,
-	"go1.13.5":    true,
-	"go1.13.6":    true,
-	"go1.13.7":    true,
-	"go1.13.8":    true,
-	"go1.13.9":    true,
-	"go1.13beta1": false,
-	"go1.13rc1":   false,
-	"go1.13rc2":   false,
-	"go1.14":      true,
-	"go1.14.1":    true,
-	"go1.14.10":   true,
-	"go1.14.11":   true,
-	"go1.14.12":   true,
-	"go1.14.13":   true,
-	"go1.14.14":   true,
-	"go1.14.15":   true,
-	"go1.14.2":    true,
-	"go1.14.3":    true,
-	"go1.14.4":    true,
-	"go1.14.5":    true,
-	"go1.14.6":    true,
-	"go1.14.7":    true,
-	"go1.14.8":    true,
-	"go1.14.9":    true,
-	"go1.14beta1": false,
-	"go1.14rc1":   false,
-	"go1.15":      true,
-	"go1.15.1":    true,
-	"go1.15.10":   true,
-	"go1.15.11":   true,
-	"go1.15.12":   true,
-	"go1.15.13":   true,
-	"go1.15.14":   true,
-	"go1.15.15":   true,
-	"go1.15.2":    true,
-	"go1.15.3":    true,
-	"go1.15.4":    true,
-	"go1.15.5":    true,
-	"go1.15.6":    true,
-	"go1.15.7":    true,
-	"go1.15.8":    true,
-	"go1.15.9":    true,
-	"go1.15beta1": false,
-	"go1.15rc1":   false,
-	"go1.15rc2":   false,
-	"go1.16":      true,
-	"go1.16.1":    true,
-	"go1.16.10":   true,
-	"go1.16.11":   true,
-	"go1.16.12":   true,
-	"go1.16.13":   true,
-	"go1.16.14":   true,
-	"go1.16.15":   true,
-	"go1.16.2":    true,
-	"go1.16.3":    true,
-	"go1.16.4":    true,
-	"go1.16.5":    true,
-	"go1.16.6":    true,
-	"go1.16.7":    true,
-	"go1.16.8":    true,
-	"go1.16.9":    true,
-	"go1.16beta1": false,
-	"go1.16rc1":   false,
-	"go1.17":      true,
-	"go1.17.1":    true,
-	"go1.17.10":   true,
-	"go1.17.11":   true,
-	"go1.17.12":   true,
-	"go1.17.13":   true,
-	"go1.17.2":    true,
-	"go1.17.3":    true,
-	"go1.17.4":    true,
-	"go1.17.5":    true,
-	"go1.17.6":    true,
-	"go1.17.7":    true,
-	"go1.17.8":    true,
-	"go1.17.9":    true,
-	"go1.17beta1": false,
-	"go1.17rc1":   false,
-	"go1.17rc2":   false,
-	"go1.18":      true,
-	"go1.18.1":    true,
-	"go1.18.10":   true,
-	"go1.18.2":    true,
-	"go1.18.3":    true,
-	"go1.18.4":    true,
-	"go1.18.5":    true,
-	"go1.18.6":    true,
-	"go1.18.7":    true,
-	"go1.18.8":    true,
-	"go1.18.9":    true,
-	"go1.18beta1": false,
-	"go1.18beta2": false,
-	"go1.18rc1":   false,
-	"go1.19":      true,
-	"go1.19.1":    true,
-	"go1.19.10":   true,
-	"go1.19.11":   true,
-	"go1.19.12":   true,
-	"go1.19.13":   true,
-	"go1.19.2":    true,
-	"go1.19.3":    true,
-	"go1.19.4":    true,
-	"go1.19.5":    true,
-	"go1.19.6":    true,
-	"go1.19.7":    true,
-	"go1.19.8":    true,
-	"go1.19.9":    true,
-	"go1.19beta1": false,
-	"go1.19rc1":   false,
-	"go1.19rc2":   false,
-	"go1.2.2":     true,
-	"go1.20":      true,
-	"go1.20.1":    true,
-	"go1.20.10":   true,
-	"go1.20.11":   true,
-	"go1.20.12":   true,
-	"go1.20.13":   true,
-	"go1.20.14":   true,
-	"go1.20.2":    true,
-	"go1.20.3":    true,
-	"go1.20.4":    true,
-	"go1.20.5":    true,
-	"go1.20.6":    true,
-	"go1.20.7":    true,
-	"go1.20.8":    true,
-	"go1.20.9":    true,
-	"go1.20rc1":   false,
-	"go1.20rc2":   false,
-	"go1.20rc3":   false,
-	"go1.21.0":    true,
-	"go1.21.1":    true,
-	"go1.21.10":   true,
-	"go1.21.11":   true,
-	"go1.21.12":   true,
-	"go1.21.13":   true,
-	"go1.21.2":    true,
-	"go1.21.3":    true,
-	"go1.21.4":    true,
-	"go1.21.5":    true,
-	"go1.21.6":    true,
-	"go1.21.7":    true,
-	"go1.21.8":    true,
-	"go1.21.9":    true,
-	"go1.21rc2":   false,
-	"go1.21rc3":   false,
-	"go1.21rc4":   false,
-	"go1.22.0":    true,
-	"go1.22.1":    true,
-	"go1.22.2":    true,
-	"go1.22.3":    true,
-	"go1.22.4":    true,
-	"go1.22.5":    true,
-	"go1.22.6":    true,
-	"go1.22.7":    true,
-	"go1.22.8":    true,
-	"go1.22.9":    true,
-	"go1.22rc1":   false,
-	"go1.22rc2":   false,
-	"go1.23.0":    true,
-	"go1.23.1":    true,
-	"go1.23.2":    true,
-	"go1.23.3":    true,
-	"go1.23rc1":   false,
-	"go1.23rc2":   false,
-	"go1.3":       true,
-	"go1.3.1":     true,
-	"go1.3.2":     true,
-	"go1.3.3":     true,
-	"go1.3rc1":    false,
-	"go1.3rc2":    false,
-	"go1.4":       true,
-	"go1.4.1":     true,
-	"go1.4.2":     true,
-	"go1.4.3":     true,
-	"go1.4beta1":  false,
-	"go1.4rc1":    false,
-	"go1.4rc2":    false,
-	"go1.5":       true,
-	"go1.5.1":     true,
-	"go1.5.2":     true,
-	"go1.5.3":     true,
-	"go1.5.4":     true,
-	"go1.5beta1":  false,
-	"go1.5beta2":  false,
-	"go1.5beta3":  false,
-	"go1.5rc1":    false,
-	"go1.6":       true,
-	"go1.6.1":     true,
-	"go1.6.2":     true,
-	"go1.6.3":     true,
-	"go1.6.4":     true,
-	"go1.6beta1":  false,
-	"go1.6beta2":  false,
-	"go1.6rc1":    false,
-	"go1.6rc2":    false,
-	"go1.7":       true,
-	"go1.7.1":     true,
-	"go1.7.3":     true,
-	"go1.7.4":     true,
-	"go1.7.5":     true,
-	"go1.7.6":     true,
-	"go1.7beta1":  false,
-	"go1.7beta2":  false,
-	"go1.7rc1":    false,
-	"go1.7rc2":    false,
-	"go1.7rc3":    false,
-	"go1.7rc4":    false,
-	"go1.7rc5":    false,
-	"go1.7rc6":    false,
-	"go1.8":       true,
-	"go1.8.1":     true,
-	"go1.8.2":     true,
-	"go1.8.3":     true,
-	"go1.8.4":     true,
-	"go1.8.5":     true,
-	"go1.8.6":     true,
-	"go1.8.7":     true,
-	"go1.8beta1":  false,
-	"go1.8beta2":  false,
-	"go1.8rc1":    false,
-	"go1.8rc2":    false,
-	"go1.8rc3":    false,
-	"go1.9":       true,
-	"go1.9.1":     true,
-	"go1.9.2":     true,
-	"go1.9.2rc2":  false,
-	"go1.9.3":     true,
-	"go1.9.4":     true,
-	"go1.9.5":     true,
-	"go1.9.6":     true,
-	"go1.9.7":     true,
-	"go1.9beta1":  false,
-	"go1.9beta2":  false,
-	"go1.9rc1":    false,
-	"go1.9rc2":    false,
-}
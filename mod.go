@@ -1,51 +1,325 @@
 package gocmd
 
 import (
-	"bytes"
+	"context"
 	"errors"
+	"fmt"
 	"io/fs"
 	"os"
-	"os/exec"
 	"strings"
 
 	"golang.org/x/mod/modfile"
 )
 
-// ModuleGoVersion reads module's go version from "go.mod" with the path from `go env GOMOD`.
-func ModuleGoVersion() (string, error) {
-	out, err := exec.Command("go", "env", "GOMOD").Output()
+// ModuleVersion is the Go version requirement declared in "go.mod".
+type ModuleVersion struct {
+	// Language is the Go language version of the module, such as "go1.21".
+	// It is the family of the go directive.
+	Language Version
+
+	// MinimumToolchain is the oldest toolchain that can build the module, such as "go1.21.0".
+	// It is the version written in the go directive.
+	MinimumToolchain Version
+
+	// Toolchain is the version written in the toolchain directive, such as "go1.22.3".
+	// It is an empty string if the directive is omitted or "default".
+	Toolchain Version
+}
+
+// Accepts reports whether the module can be built with the go command of the given version.
+// The version is accepted if it belongs to the family of Language and is not older than MinimumToolchain.
+// Like the go command, a go directive without patch version such as "go 1.19" accepts prereleases of the family too.
+// If the toolchain directive exists, the version that belongs to its family and is not older than Toolchain is also accepted.
+//
+//	go 1.21.0
+//	toolchain go1.22.3
+//
+// In the case above, "go1.21.0", "go1.21.5" and "go1.22.3" are accepted, but "go1.21rc2" and "go1.22.1" are not.
+func (m ModuleVersion) Accepts(version Version) bool {
+	if sameFamily(string(version), string(m.Language)) && atLeast(version, m.MinimumToolchain) {
+		return true
+	}
+	return m.Toolchain != "" &&
+		sameFamily(string(version), string(m.Toolchain)) && version.Compare(m.Toolchain) >= 0
+}
+
+// ModuleInfo is the information read from "go.mod".
+type ModuleInfo struct {
+	// Path is the module path.
+	Path string
+
+	// ModuleVersion is read from the go directive and the toolchain directive.
+	ModuleVersion
+
+	// Godebug is the list of default GODEBUG settings from godebug directives.
+	Godebug []Godebug
+
+	// Tool is the list of package paths from tool directives.
+	Tool []string
+
+	// Ignore is the list of directory paths from ignore directives.
+	Ignore []string
+}
+
+// Godebug is a key-value pair of godebug directive.
+type Godebug struct {
+	Key   string
+	Value string
+}
+
+// UnknownDirectiveError is returned when "go.mod" has directives that this package does not know,
+// which may have been introduced by a newer release of Go.
+// The other directives are still read, so that Info.ModuleVersion is available.
+type UnknownDirectiveError struct {
+	File       string
+	Directives []string
+	Info       *ModuleInfo
+}
+
+func (e *UnknownDirectiveError) Error() string {
+	return fmt.Sprintf("%s: unknown directive: %s", e.File, strings.Join(e.Directives, ", "))
+}
+
+var knownDirectives = map[string]bool{
+	"module":    true,
+	"go":        true,
+	"toolchain": true,
+	"godebug":   true,
+	"require":   true,
+	"exclude":   true,
+	"replace":   true,
+	"retract":   true,
+	"tool":      true,
+	"ignore":    true,
+}
+
+// ParseModuleInfo parses the content of "go.mod".
+// file is used in error messages.
+// If the content has unknown directives, it returns *UnknownDirectiveError that holds the result of the other directives.
+func ParseModuleInfo(file string, data []byte) (*ModuleInfo, error) {
+	// ParseLax does not fail on unknown directives, so that we can find them first.
+	lax, err := modfile.ParseLax(file, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	var unknown []string
+	stmt := lax.Syntax.Stmt[:0]
+	for _, x := range lax.Syntax.Stmt {
+		var verb string
+		switch x := x.(type) {
+		case *modfile.Line:
+			verb = x.Token[0]
+		case *modfile.LineBlock:
+			verb = x.Token[0]
+		default:
+			stmt = append(stmt, x)
+			continue
+		}
+		if !knownDirectives[verb] {
+			unknown = append(unknown, verb)
+			continue
+		}
+		stmt = append(stmt, x)
+	}
+	if len(unknown) > 0 {
+		lax.Syntax.Stmt = stmt
+		data = modfile.Format(lax.Syntax)
+	}
+
+	mod, err := modfile.Parse(file, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if mod.Go == nil {
+		return nil, errors.New("invalid module file: go version not found")
+	}
+	var toolchain string
+	if mod.Toolchain != nil {
+		toolchain = mod.Toolchain.Name
+	}
+	ver, err := newModuleVersion(mod.Go.Version, toolchain)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ModuleInfo{
+		ModuleVersion: ver,
+	}
+	if mod.Module != nil {
+		info.Path = mod.Module.Mod.Path
+	}
+	for _, g := range mod.Godebug {
+		info.Godebug = append(info.Godebug, Godebug{
+			Key:   g.Key,
+			Value: g.Value,
+		})
+	}
+	for _, t := range mod.Tool {
+		info.Tool = append(info.Tool, t.Path)
+	}
+	for _, i := range mod.Ignore {
+		info.Ignore = append(info.Ignore, i.Path)
+	}
+
+	if len(unknown) > 0 {
+		return nil, &UnknownDirectiveError{
+			File:       file,
+			Directives: unknown,
+			Info:       info,
+		}
+	}
+	return info, nil
+}
+
+func (r *Resolver) goModPath(ctx context.Context) (string, error) {
+	path, err := r.goEnv(ctx, "go", "GOMOD", nil)
 	if err != nil {
 		return "", err
 	}
-	path := string(bytes.TrimSpace(out))
 	if path == "" || path == os.DevNull {
 		return "", fs.ErrNotExist
 	}
+	return path, nil
+}
+
+// ReadModuleInfo reads "go.mod" with the path from `go env GOMOD`.
+// See ParseModuleInfo for details.
+func ReadModuleInfo() (*ModuleInfo, error) {
+	return ReadModuleInfoContext(context.Background())
+}
+
+// ReadModuleInfo reads "go.mod" found from the working directory of r.
+func (r *Resolver) ReadModuleInfo() (*ModuleInfo, error) {
+	return r.ReadModuleInfoContext(context.Background())
+}
+
+// ReadModuleInfoContext is like ReadModuleInfo, but the given context is applied to `go env GOMOD`.
+func ReadModuleInfoContext(ctx context.Context) (*ModuleInfo, error) {
+	return defaultResolver.ReadModuleInfoContext(ctx)
+}
+
+// ReadModuleInfoContext is like r.ReadModuleInfo, but the given context is applied to `go env GOMOD`.
+func (r *Resolver) ReadModuleInfoContext(ctx context.Context) (*ModuleInfo, error) {
+	path, err := r.goModPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return readModuleInfo(path)
+}
+
+func readModuleInfo(path string) (*ModuleInfo, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return ParseModuleInfo(path, data)
+}
+
+func (m ModuleVersion) allows(version Version) bool {
+	return atLeast(version, m.MinimumToolchain)
+}
+
+func (m ModuleVersion) goVersion() ModuleVersion {
+	return m
+}
+
+// atLeast reports whether version is not older than the version from go directive.
+// The go directive without patch version such as "go1.21" is satisfied by the prereleases of the family too.
+func atLeast(version, goVersion Version) bool {
+	if goVersion == goVersion.Family() {
+		return version.Family().Compare(goVersion) >= 0
+	}
+	return version.Compare(goVersion) >= 0
+}
+
+// ModuleGoVersion reads module's go version from "go.mod" with the path from `go env GOMOD`.
+// Both the go directive and the toolchain directive introduced in Go 1.21 are read.
+// Unknown directives in "go.mod" are ignored.
+func ModuleGoVersion() (ModuleVersion, error) {
+	return ModuleGoVersionContext(context.Background())
+}
+
+// ModuleGoVersion reads the go and toolchain directives of "go.mod" found from the working directory of r.
+func (r *Resolver) ModuleGoVersion() (ModuleVersion, error) {
+	return r.ModuleGoVersionContext(context.Background())
+}
+
+// ModuleGoVersionContext is like ModuleGoVersion, but the given context is applied to `go env GOMOD`.
+func ModuleGoVersionContext(ctx context.Context) (ModuleVersion, error) {
+	return defaultResolver.ModuleGoVersionContext(ctx)
+}
+
+// ModuleGoVersionContext is like r.ModuleGoVersion, but the given context is applied to `go env GOMOD`.
+func (r *Resolver) ModuleGoVersionContext(ctx context.Context) (ModuleVersion, error) {
+	path, err := r.goModPath(ctx)
+	if err != nil {
+		return ModuleVersion{}, err
 	}
-	mod, err := modfile.Parse("go.mod", data, nil)
+	return readModuleGoVersion(path)
+}
+
+// readModuleGoVersion reads the go and toolchain directives from "go.mod", ignoring unknown directives.
+func readModuleGoVersion(path string) (ModuleVersion, error) {
+	info, err := readModuleInfo(path)
 	if err != nil {
-		return "", err
+		var e *UnknownDirectiveError
+		if errors.As(err, &e) {
+			return e.Info.ModuleVersion, nil
+		}
+		return ModuleVersion{}, err
 	}
-	if mod.Go == nil {
-		return "", errors.New("invalid module file: go version not found")
+	return info.ModuleVersion, nil
+}
+
+// newModuleVersion builds ModuleVersion from the arguments of go directive and toolchain directive.
+func newModuleVersion(goVersion, toolchain string) (ModuleVersion, error) {
+	min, err := ParseVersion("go" + goVersion)
+	if err != nil {
+		return ModuleVersion{}, fmt.Errorf("invalid module file: %w", err)
+	}
+	m := ModuleVersion{
+		Language:         min.Family(),
+		MinimumToolchain: min,
+	}
+	if toolchain != "" && toolchain != "default" {
+		// custom toolchain has a suffix like "go1.21.3-bigcorp"
+		name, _, _ := strings.Cut(toolchain, "-")
+		m.Toolchain, err = ParseVersion(name)
+		if err != nil {
+			return ModuleVersion{}, fmt.Errorf("invalid module file: toolchain: %w", err)
+		}
 	}
-	return "go" + mod.Go.Version, nil
+	return m, nil
 }
 
 var ErrUnexpectedGoVersion = errors.New("unexpected go version in go.mod")
 
 // ValidModuleGoVersion compares the given version and module's Go version.
 // Go version of the module will be read from "go.mod" with the path from `go env GOMOD`.
+// See ModuleVersion.Accepts for the rules.
 func ValidModuleGoVersion(version string) error {
-	err := ValidVersion(version)
+	return ValidModuleGoVersionContext(context.Background(), version)
+}
+
+// ValidModuleGoVersion checks the version against "go.mod" found from the working directory of r.
+func (r *Resolver) ValidModuleGoVersion(version string) error {
+	return r.ValidModuleGoVersionContext(context.Background(), version)
+}
+
+// ValidModuleGoVersionContext is like ValidModuleGoVersion, but the given context is applied to
+// the fetch of the release index and `go env GOMOD`.
+func ValidModuleGoVersionContext(ctx context.Context, version string) error {
+	return defaultResolver.ValidModuleGoVersionContext(ctx, version)
+}
+
+// ValidModuleGoVersionContext is like r.ValidModuleGoVersion, but the given context is applied to `go env GOMOD`.
+func (r *Resolver) ValidModuleGoVersionContext(ctx context.Context, version string) error {
+	err := r.ValidVersionContext(ctx, version)
 	if err != nil {
 		return err
 	}
 
-	expected, err := ModuleGoVersion()
+	expected, err := r.ModuleGoVersionContext(ctx)
 	if err != nil {
 		return err
 	}
@@ -53,7 +327,13 @@ func ValidModuleGoVersion(version string) error {
 	// version=go1.19.1
 	// expected=go1.19
 	// => valid
-	if strings.HasPrefix(version, expected) {
+	// version=go1.19.1
+	// expected=go1.1
+	// => invalid
+	// version=go1.21.5
+	// expected=go1.21.0
+	// => valid
+	if expected.Accepts(Version(version)) {
 		return nil
 	}
 	return ErrUnexpectedGoVersion
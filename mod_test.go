@@ -3,65 +3,85 @@ package gocmd
 import (
 	"errors"
 	"io/fs"
-	"os"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
-func chdir(t *testing.T, path string) {
-	t.Helper()
+func TestModuleGoVersion(t *testing.T) {
 
-	wd, err := os.Getwd()
-	if err != nil {
-		t.Fatal(err)
-	}
-	err = os.Chdir(path)
-	if err != nil {
-		t.Fatal(err)
-	}
-	t.Cleanup(func() {
-		err := os.Chdir(wd)
+	t.Run("valid", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/valid"))
+
+		ver, err := r.ModuleGoVersion()
 		if err != nil {
 			t.Fatal(err)
 		}
+		diff := cmp.Diff(ModuleVersion{
+			Language:         "go1.19",
+			MinimumToolchain: "go1.19",
+		}, ver)
+		if diff != "" {
+			t.Fatal(diff)
+		}
 	})
-}
 
-func TestModuleGoVersion(t *testing.T) {
+	t.Run("toolchain", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/toolchain"))
 
-	t.Run("valid", func(t *testing.T) {
-		chdir(t, "testdata/valid")
+		ver, err := r.ModuleGoVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff := cmp.Diff(ModuleVersion{
+			Language:         "go1.21",
+			MinimumToolchain: "go1.21.0",
+			Toolchain:        "go1.22.3",
+		}, ver)
+		if diff != "" {
+			t.Fatal(diff)
+		}
+	})
 
-		ver, err := ModuleGoVersion()
+	t.Run("unknown directive", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/unknown"))
+
+		ver, err := r.ModuleGoVersion()
 		if err != nil {
 			t.Fatal(err)
 		}
-		if ver != "go1.19" {
-			t.Fatalf("got unexpected version: %s", ver)
+		diff := cmp.Diff(ModuleVersion{
+			Language:         "go1.22",
+			MinimumToolchain: "go1.22.0",
+			Toolchain:        "go1.22.5",
+		}, ver)
+		if diff != "" {
+			t.Fatal(diff)
 		}
 	})
 
 	t.Run("invalid", func(t *testing.T) {
-		chdir(t, "testdata/invalid")
+		r := NewResolver(WithDir("testdata/invalid"))
 
-		_, err := ModuleGoVersion()
+		_, err := r.ModuleGoVersion()
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 	})
 
 	t.Run("empty", func(t *testing.T) {
-		chdir(t, "testdata/empty")
+		r := NewResolver(WithDir("testdata/empty"))
 
-		_, err := ModuleGoVersion()
+		_, err := r.ModuleGoVersion()
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		chdir(t, t.TempDir())
+		r := NewResolver(WithDir(t.TempDir()))
 
-		_, err := ModuleGoVersion()
+		_, err := r.ModuleGoVersion()
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
@@ -71,22 +91,163 @@ func TestModuleGoVersion(t *testing.T) {
 	})
 }
 
+func TestParseModuleInfo(t *testing.T) {
+	t.Parallel()
+
+	const modern = `module example.com/m
+
+go 1.24.0
+
+toolchain go1.24.2
+
+godebug (
+	default=go1.21
+	panicnil=1
+)
+
+godebug asynctimerchan=0
+
+tool golang.org/x/tools/cmd/stringer
+
+tool (
+	example.com/m/cmd/gen
+)
+
+ignore ./node_modules
+
+require golang.org/x/tools v0.30.0
+`
+	want := &ModuleInfo{
+		Path: "example.com/m",
+		ModuleVersion: ModuleVersion{
+			Language:         "go1.24",
+			MinimumToolchain: "go1.24.0",
+			Toolchain:        "go1.24.2",
+		},
+		Godebug: []Godebug{
+			{Key: "default", Value: "go1.21"},
+			{Key: "panicnil", Value: "1"},
+			{Key: "asynctimerchan", Value: "0"},
+		},
+		Tool:   []string{"golang.org/x/tools/cmd/stringer", "example.com/m/cmd/gen"},
+		Ignore: []string{"./node_modules"},
+	}
+
+	t.Run("modern", func(t *testing.T) {
+		t.Parallel()
+
+		info, err := ParseModuleInfo("go.mod", []byte(modern))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, info); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("unknown directive", func(t *testing.T) {
+		t.Parallel()
+
+		data := modern + `
+frobnicate ./internal
+
+future (
+	something
+)
+`
+		_, err := ParseModuleInfo("go.mod", []byte(data))
+		var e *UnknownDirectiveError
+		if !errors.As(err, &e) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if diff := cmp.Diff([]string{"frobnicate", "future"}, e.Directives); diff != "" {
+			t.Fatal(diff)
+		}
+		if diff := cmp.Diff(want, e.Info); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("invalid known directive", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := ParseModuleInfo("go.mod", []byte("module m\n\ngo 1.21.0\n\ntoolchain 1.21.0\n"))
+		if err == nil {
+			t.Fatal("unexpected success")
+		}
+	})
+}
+
+func TestModuleVersion_Accepts(t *testing.T) {
+	t.Parallel()
+
+	for _, i := range []struct {
+		goVersion, toolchain string
+		accepted             []Version
+		rejected             []Version
+	}{
+		{
+			goVersion: "1.19",
+			accepted:  []Version{"go1.19beta1", "go1.19", "go1.19.13"},
+			rejected:  []Version{"go1.18.10", "go1.20", "go1.1"},
+		}, {
+			goVersion: "1.21.0",
+			accepted:  []Version{"go1.21.0", "go1.21.5"},
+			rejected:  []Version{"go1.21rc2", "go1.20.14", "go1.22.0"},
+		}, {
+			goVersion: "1.21",
+			accepted:  []Version{"go1.21rc2", "go1.21.0", "go1.21.5"},
+			rejected:  []Version{"go1.20.14", "go1.22.0", "go1.2"},
+		}, {
+			goVersion: "1.21.0",
+			toolchain: "go1.22.3",
+			accepted:  []Version{"go1.21.0", "go1.21.5", "go1.22.3", "go1.22.8"},
+			rejected:  []Version{"go1.21rc2", "go1.22.1", "go1.23.0"},
+		}, {
+			goVersion: "1.21.3",
+			toolchain: "default",
+			accepted:  []Version{"go1.21.3"},
+			rejected:  []Version{"go1.21.2"},
+		}, {
+			goVersion: "1.22.0",
+			toolchain: "go1.22.4-bigcorp",
+			accepted:  []Version{"go1.22.0", "go1.22.4"},
+			rejected:  []Version{"go1.21.0"},
+		},
+	} {
+		m, err := newModuleVersion(i.goVersion, i.toolchain)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range i.accepted {
+			if !m.Accepts(v) {
+				t.Errorf("go %s, toolchain %s: %s expected to be accepted", i.goVersion, i.toolchain, v)
+			}
+		}
+		for _, v := range i.rejected {
+			if m.Accepts(v) {
+				t.Errorf("go %s, toolchain %s: %s expected to be rejected", i.goVersion, i.toolchain, v)
+			}
+		}
+	}
+}
+
 func TestValidModuleGoVersion(t *testing.T) {
 	t.Run("valid", func(t *testing.T) {
-		// move working directory to testdata to avoid hitting module's "go.mod"
-		chdir(t, "testdata/valid")
+		// testdata has its own "go.mod", so that the module's "go.mod" is not hit
+		r := NewResolver(WithDir("testdata/valid"))
 
-		err := ValidModuleGoVersion("go1.19")
+		err := r.ValidModuleGoVersion("go1.19")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = ValidModuleGoVersion("go1.19beta1")
+		err = r.ValidModuleGoVersion("go1.19beta1")
 		if err != nil {
 			t.Fatal(err)
 		}
 
-		err = ValidModuleGoVersion("go1.18.5")
+		err = r.ValidModuleGoVersion("go1.18.5")
 		if err == nil {
 			t.Fatalf("unexpected success")
 		}
@@ -94,7 +255,7 @@ func TestValidModuleGoVersion(t *testing.T) {
 			t.Fatalf("unexpected error: %s", err)
 		}
 
-		err = ValidModuleGoVersion("unknown")
+		err = r.ValidModuleGoVersion("unknown")
 		if err == nil {
 			t.Fatalf("unexpected success")
 		}
@@ -103,28 +264,63 @@ func TestValidModuleGoVersion(t *testing.T) {
 		}
 	})
 
+	t.Run("toolchain", func(t *testing.T) {
+		// go.mod declares "go 1.21.0" and "toolchain go1.22.3"
+		r := NewResolver(WithDir("testdata/toolchain"))
+
+		for _, v := range []string{"go1.21.0", "go1.21.5", "go1.22.3"} {
+			err := r.ValidModuleGoVersion(v)
+			if err != nil {
+				t.Fatalf("%s: %s", v, err)
+			}
+		}
+		for _, v := range []string{"go1.21rc2", "go1.22.1", "go1.20.14"} {
+			err := r.ValidModuleGoVersion(v)
+			if !errors.Is(err, ErrUnexpectedGoVersion) {
+				t.Fatalf("%s: unexpected error: %v", v, err)
+			}
+		}
+	})
+
+	t.Run("family collision", func(t *testing.T) {
+		// go.mod declares "go 1.2"
+		r := NewResolver(WithDir("testdata/collision"))
+
+		err := r.ValidModuleGoVersion("go1.2.2")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, v := range []string{"go1.20", "go1.21.0", "go1.23.3"} {
+			err = r.ValidModuleGoVersion(v)
+			if !errors.Is(err, ErrUnexpectedGoVersion) {
+				t.Fatalf("%s: unexpected error: %v", v, err)
+			}
+		}
+	})
+
 	t.Run("invalid", func(t *testing.T) {
-		chdir(t, "testdata/invalid")
+		r := NewResolver(WithDir("testdata/invalid"))
 
-		err := ValidModuleGoVersion("go1.19")
+		err := r.ValidModuleGoVersion("go1.19")
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 	})
 
 	t.Run("empty", func(t *testing.T) {
-		chdir(t, "testdata/empty")
+		r := NewResolver(WithDir("testdata/empty"))
 
-		err := ValidModuleGoVersion("go1.19")
+		err := r.ValidModuleGoVersion("go1.19")
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 	})
 
 	t.Run("not found", func(t *testing.T) {
-		chdir(t, t.TempDir())
+		r := NewResolver(WithDir(t.TempDir()))
 
-		err := ValidModuleGoVersion("go1.19")
+		err := r.ValidModuleGoVersion("go1.19")
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
@@ -0,0 +1,98 @@
+package gocmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Mode is a set of strategies to determine go command.
+// Modes are bit flags and can be combined, such as ModeExact|ModeLatest|ModeFallback.
+// Determine, DetermineFromModuleGoVersion and DetermineFromWorkspace try the strategies in the following order,
+// and return the first command found.
+//  1. ModeExact
+//  2. ModeLatest
+//  3. ModeMinimum or ModeNewest
+//  4. ModeFallback
+//
+// ModeStable is not a strategy, but a modifier that excludes betas and release candidates
+// from ModeLatest, ModeMinimum and ModeNewest.
+//
+// In DetermineFromModuleGoVersion and DetermineFromWorkspace, ModeExact behaves as ModeLatest,
+// because the requirement of "go.mod" and "go.work" is satisfied by a family of versions.
+type Mode uint8
+
+const (
+	ModeExact Mode = 1 << iota
+	ModeLatest
+	ModeFallback
+	ModeMinimum
+	ModeNewest
+	ModeStable
+
+	modeStrategies = ModeExact | ModeLatest | ModeMinimum | ModeNewest
+	modeAll        = modeStrategies | ModeFallback | ModeStable
+)
+
+var ErrInvalidMode = errors.New("invalid mode")
+
+var modeNames = []struct {
+	mode Mode
+	name string
+}{
+	{ModeExact, "ModeExact"},
+	{ModeLatest, "ModeLatest"},
+	{ModeMinimum, "ModeMinimum"},
+	{ModeNewest, "ModeNewest"},
+	{ModeFallback, "ModeFallback"},
+	{ModeStable, "ModeStable"},
+}
+
+// String returns the names of flags joined by "|", such as "ModeLatest|ModeFallback".
+func (m Mode) String() string {
+	var names []string
+	for _, n := range modeNames {
+		if m&n.mode != 0 {
+			names = append(names, n.name)
+		}
+	}
+	if rest := m &^ modeAll; rest != 0 {
+		names = append(names, fmt.Sprintf("Mode(%#x)", uint8(rest)))
+	}
+	if len(names) == 0 {
+		return "Mode(0)"
+	}
+	return strings.Join(names, "|")
+}
+
+// normalize validates the combination of flags, and fills implied flags.
+// Zero Mode is replaced with zero for compatibility.
+// If no strategy is given, ModeLatest is implied, so that ModeFallback alone behaves as before.
+func (m Mode) normalize(zero Mode) (Mode, error) {
+	if m == 0 {
+		return zero, nil
+	}
+	if m&^modeAll != 0 {
+		return 0, fmt.Errorf("%w: unknown flags in %s", ErrInvalidMode, m)
+	}
+	if m&ModeMinimum != 0 && m&ModeNewest != 0 {
+		return 0, fmt.Errorf("%w: ModeMinimum and ModeNewest are exclusive: %s", ErrInvalidMode, m)
+	}
+	if m&modeStrategies == 0 {
+		m |= ModeLatest
+	}
+	if m&ModeStable != 0 && m&(ModeLatest|ModeMinimum|ModeNewest) == 0 {
+		return 0, fmt.Errorf("%w: ModeStable has no effect on ModeExact: %s", ErrInvalidMode, m)
+	}
+	return m, nil
+}
+
+// accept returns the filter of versions for ModeLatest, ModeMinimum and ModeNewest.
+func (m Mode) accept(accept func(Version) bool) func(Version) bool {
+	if m&ModeStable == 0 {
+		return accept
+	}
+	return func(v Version) bool {
+		return v.Kind() == KindRelease && (accept == nil || accept(v))
+	}
+}
@@ -0,0 +1,55 @@
+package gocmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMode_normalize(t *testing.T) {
+	t.Parallel()
+
+	for _, i := range []struct {
+		mode, zero, expected Mode
+		error                bool
+	}{
+		{mode: 0, zero: ModeLatest | ModeFallback, expected: ModeLatest | ModeFallback},
+		{mode: 0, zero: ModeLatest, expected: ModeLatest},
+		{mode: ModeExact, expected: ModeExact},
+		{mode: ModeFallback, expected: ModeLatest | ModeFallback},
+		{mode: ModeStable, expected: ModeLatest | ModeStable},
+		{mode: ModeExact | ModeLatest | ModeFallback, expected: ModeExact | ModeLatest | ModeFallback},
+		{mode: ModeLatest | ModeMinimum | ModeStable, expected: ModeLatest | ModeMinimum | ModeStable},
+		{mode: ModeMinimum | ModeNewest, error: true},
+		{mode: ModeExact | ModeStable, error: true},
+		{mode: 1 << 7, error: true},
+	} {
+		got, err := i.mode.normalize(i.zero)
+		if i.error {
+			if !errors.Is(err, ErrInvalidMode) {
+				t.Errorf("%s: unexpected error: %v", i.mode, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", i.mode, err)
+		}
+		if got != i.expected {
+			t.Errorf("%s: expected %s, got %s", i.mode, i.expected, got)
+		}
+	}
+}
+
+func TestMode_String(t *testing.T) {
+	t.Parallel()
+
+	for mode, expected := range map[Mode]string{
+		0:                         "Mode(0)",
+		ModeExact:                 "ModeExact",
+		ModeLatest | ModeFallback: "ModeLatest|ModeFallback",
+		ModeNewest | 1<<7:         "ModeNewest|Mode(0x80)",
+	} {
+		if mode.String() != expected {
+			t.Errorf("expected %s, got %s", expected, mode.String())
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// OfflineEnv is the environment variable to enable offline mode, such as GOCMD_OFFLINE=1.
+// It is used when SetOffline is not called.
+const OfflineEnv = internal.OfflineEnv
+
+var (
+	// ErrOffline is returned when the network access is required in offline mode.
+	ErrOffline = internal.ErrOffline
+
+	// ErrUnknownOffline is returned when the version is not known in offline mode.
+	// Unlike ErrInvalidVersion, the version may exist.
+	ErrUnknownOffline = errors.New("unknown version in offline mode")
+)
+
+// SetOffline enables or disables offline mode.
+// In offline mode, the network is never accessed. ValidVersion, StableVersion, Lookup and so on answer from
+// the embedded list, the cache of the release index and the local file source set by SetReleaseIndex.
+// If the version is not found in them, they return ErrUnknownOffline. Install returns ErrOffline.
+func SetOffline(offline bool) {
+	defaultResolver.SetOffline(offline)
+}
+
+// SetOffline enables or disables offline mode of r, regardless of OfflineEnv and other Resolvers.
+func (r *Resolver) SetOffline(offline bool) {
+	r.catalog.SetOffline(offline)
+}
+
+// Offline reports whether offline mode is enabled by SetOffline or OfflineEnv.
+func Offline() bool {
+	return defaultResolver.Offline()
+}
+
+// Offline reports whether offline mode of r is enabled.
+func (r *Resolver) Offline() bool {
+	return r.catalog.Offline()
+}
+
+// fetchOnce fetches the release index, or returns ErrUnknownOffline in offline mode.
+func (r *Resolver) fetchOnce(ctx context.Context) (bool, error) {
+	fetched, err := r.catalog.FetchOnce(ctx)
+	if errors.Is(err, internal.ErrOffline) {
+		return false, ErrUnknownOffline
+	}
+	return fetched, err
+}
@@ -0,0 +1,70 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetOffline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+	r.SetOffline(true)
+	if !r.Offline() {
+		t.Fatal("offline mode expected")
+	}
+
+	err := r.ValidVersion("go1.21.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.ValidVersion(futureVersion(2))
+	if !errors.Is(err, ErrUnknownOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = r.StableVersion(futureVersion(2))
+	if !errors.Is(err, ErrUnknownOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = r.Lookup(futureVersion(2))
+	if !errors.Is(err, ErrUnknownOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, _, err = r.Determine(futureVersion(2), ModeLatest)
+	if !errors.Is(err, ErrUnknownOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = r.install(context.Background(), "go1.16.15", archiveFile("go1.16.15.linux-amd64.tar.gz", nil), t.TempDir(), &installConfig{
+		baseURL: srv.URL,
+		goos:    "linux",
+		goarch:  "amd64",
+	})
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestSetOffline_localSource(t *testing.T) {
+	version := futureVersion(1)
+	dir := t.TempDir()
+	writeReleaseIndex(t, filepath.Join(dir, "releases.json"), version)
+	r := newIndexResolver(t, dir)
+	r.SetOffline(true)
+
+	// local file is not the network
+	err := r.ValidVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.ValidVersion(futureVersion(2))
+	if !errors.Is(err, ErrUnknownOffline) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package gocmd
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+)
+
+func toVersions(list []string) []Version {
+	versions := make([]Version, 0, len(list))
+	for _, v := range list {
+		versions = append(versions, Version(v))
+	}
+	// in descending order
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Compare(versions[j]) > 0
+	})
+	return versions
+}
+
+// Refresh fetches the release index again, even if it has been fetched, and swaps the known versions atomically.
+// The functions in other goroutines see either the old versions or the new ones, and are not blocked while fetching.
+// The cached index on disk is revalidated regardless of its TTL.
+//
+// It returns the versions newly appeared, in descending order. In offline mode, it returns ErrOffline.
+func Refresh(ctx context.Context) ([]Version, error) {
+	return defaultResolver.Refresh(ctx)
+}
+
+// Refresh fetches the release index of r again, and merges it into the versions known by r.
+func (r *Resolver) Refresh(ctx context.Context) ([]Version, error) {
+	added, err := r.catalog.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return toVersions(added), nil
+}
+
+// Subscribe registers fn, which is called with the versions newly appeared in the release index, in descending order.
+// It is called after Refresh, the background refresher by StartRefresher, or the fetch for an unknown version.
+// fn is called in the goroutine that fetched the index, so it should return quickly.
+//
+// It returns the function to unsubscribe.
+func Subscribe(fn func(added []Version)) (unsubscribe func()) {
+	return defaultResolver.Subscribe(fn)
+}
+
+// Subscribe registers fn, which is called with the versions newly known by r.
+func (r *Resolver) Subscribe(fn func(added []Version)) (unsubscribe func()) {
+	return r.catalog.Subscribe(func(added []string) {
+		fn(toVersions(added))
+	})
+}
+
+// StartRefresher starts the goroutine that calls Refresh at every interval, for long-running processes.
+// Errors from Refresh are passed to onError, if it is not nil.
+//
+// It returns the function to stop the refresher, which cancels the running Refresh and waits for the goroutine to finish.
+func StartRefresher(interval time.Duration, onError func(error)) (stop func()) {
+	return defaultResolver.StartRefresher(interval, onError)
+}
+
+// StartRefresher starts refreshing the release index of r periodically.
+func (r *Resolver) StartRefresher(interval time.Duration, onError func(error)) (stop func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+			_, err := r.Refresh(ctx)
+			if err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			cancel()
+			<-done
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package gocmd
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRefresh(t *testing.T) {
+	first := futureVersion(1)
+	index := filepath.Join(t.TempDir(), "index.json")
+	writeReleaseIndex(t, index, first)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// no Last-Modified, because the index is updated within a second
+		data, err := os.ReadFile(index)
+		if err != nil {
+			t.Error(err)
+		}
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+
+	var notified [][]Version
+	unsubscribe := r.Subscribe(func(added []Version) {
+		notified = append(notified, added)
+	})
+	t.Cleanup(unsubscribe)
+
+	added, err := r.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Version{Version(first)}, added); diff != "" {
+		t.Fatal(diff)
+	}
+
+	// a new release is shipped
+	second := futureVersion(2)
+	writeReleaseIndex(t, index, first, second)
+	added, err = r.Refresh(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]Version{Version(second)}, added); diff != "" {
+		t.Fatal(diff)
+	}
+	err = r.ValidVersion(second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff([][]Version{{Version(first)}, {Version(second)}}, notified); diff != "" {
+		t.Fatal(diff)
+	}
+}
+
+func TestRefresh_offline(t *testing.T) {
+	r := NewResolver()
+	r.SetOffline(true)
+
+	_, err := r.Refresh(context.Background())
+	if err != ErrOffline {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestStartRefresher(t *testing.T) {
+	version := Version(futureVersion(1))
+	index := filepath.Join(t.TempDir(), "index.json")
+	writeReleaseIndex(t, index, string(version))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, index)
+	}))
+	t.Cleanup(srv.Close)
+	r := newIndexResolver(t, srv.URL+"/dl/")
+
+	found := make(chan struct{})
+	var once sync.Once
+	unsubscribe := r.Subscribe(func(added []Version) {
+		for _, v := range added {
+			if v == version {
+				once.Do(func() {
+					close(found)
+				})
+			}
+		}
+	})
+	t.Cleanup(unsubscribe)
+
+	stop := r.StartRefresher(10*time.Millisecond, func(err error) {
+		t.Error(err)
+	})
+	defer stop()
+	select {
+	case <-found:
+	case <-time.After(10 * time.Second):
+		t.Fatal("not refreshed")
+	}
+	stop()
+}
@@ -0,0 +1,129 @@
+package gocmd
+
+import (
+	"context"
+	"sort"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// FileKind is the kind of the file of a release.
+type FileKind string
+
+const (
+	FileKindArchive   FileKind = "archive"
+	FileKindInstaller FileKind = "installer"
+	FileKindSource    FileKind = "source"
+)
+
+// File is a downloadable file of a release.
+type File struct {
+	Filename string
+	OS       string
+	Arch     string
+	Kind     FileKind
+	Size     int64
+	SHA256   string
+}
+
+// Release is a released version of Go, and its files.
+type Release struct {
+	Version Version
+	Stable  bool
+	Files   []File
+}
+
+// File returns the file for the given GOOS, GOARCH and kind.
+// For FileKindSource, goos and goarch are ignored.
+func (r *Release) File(goos, goarch string, kind FileKind) (File, bool) {
+	for _, f := range r.Files {
+		if f.Kind != kind {
+			continue
+		}
+		if kind == FileKindSource || (f.OS == goos && f.Arch == goarch) {
+			return f, true
+		}
+	}
+	return File{}, false
+}
+
+func newRelease(version string, stable bool, files []internal.File) Release {
+	r := Release{
+		Version: Version(version),
+		Stable:  stable,
+	}
+	for _, f := range files {
+		r.Files = append(r.Files, File{
+			Filename: f.Filename,
+			OS:       f.OS,
+			Arch:     f.Arch,
+			Kind:     FileKind(f.Kind),
+			Size:     f.Size,
+			SHA256:   f.SHA256,
+		})
+	}
+	return r
+}
+
+func (r *Resolver) knownRelease(version string) (Release, bool) {
+	var rel Release
+	var ok bool
+	r.catalog.Releases(func(versions map[string]bool, files map[string][]internal.File) {
+		var stable bool
+		stable, ok = versions[version]
+		if ok {
+			rel = newRelease(version, stable, files[version])
+		}
+	})
+	return rel, ok
+}
+
+// LookupRelease returns the release of the given version, including its files.
+// The source of correctness is the same as ValidVersion.
+func LookupRelease(version string) (*Release, error) {
+	return LookupReleaseContext(context.Background(), version)
+}
+
+// LookupRelease returns the release of the given version known by r, fetching the release index of r if needed.
+func (r *Resolver) LookupRelease(version string) (*Release, error) {
+	return r.LookupReleaseContext(context.Background(), version)
+}
+
+// LookupReleaseContext is like LookupRelease, but the given context is applied to the fetch of the release index.
+func LookupReleaseContext(ctx context.Context, version string) (*Release, error) {
+	return defaultResolver.LookupReleaseContext(ctx, version)
+}
+
+// LookupReleaseContext is like r.LookupRelease, but the given context is applied to the fetch of the release index.
+func (r *Resolver) LookupReleaseContext(ctx context.Context, version string) (*Release, error) {
+	err := r.ValidVersionContext(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	rel, ok := r.knownRelease(version)
+	if !ok {
+		return nil, ErrInvalidVersion
+	}
+	return &rel, nil
+}
+
+// Releases returns all known releases in descending order.
+// Releases newer than the embedded list are included only after they are fetched by ValidVersion and so on.
+func Releases() []Release {
+	return defaultResolver.Releases()
+}
+
+// Releases returns the releases known by r, from the newest one.
+func (r *Resolver) Releases() []Release {
+	var list []Release
+	r.catalog.Releases(func(versions map[string]bool, files map[string][]internal.File) {
+		list = make([]Release, 0, len(versions))
+		for v, stable := range versions {
+			list = append(list, newRelease(v, stable, files[v]))
+		}
+	})
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Version.Compare(list[j].Version) > 0
+	})
+	return list
+}
@@ -0,0 +1,76 @@
+package gocmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupRelease(t *testing.T) {
+	t.Parallel()
+
+	r, err := LookupRelease("go1.21.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "go1.21.0" || !r.Stable {
+		t.Fatalf("unexpected release: %#v", r)
+	}
+
+	r, err = LookupRelease("go1.22rc1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Version != "go1.22rc1" || r.Stable {
+		t.Fatalf("unexpected release: %#v", r)
+	}
+
+	_, err = LookupRelease("../invalid")
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReleases(t *testing.T) {
+	t.Parallel()
+
+	releases := Releases()
+	if len(releases) == 0 {
+		t.Fatal("no releases")
+	}
+	for i := 1; i < len(releases); i++ {
+		if releases[i-1].Version.Compare(releases[i].Version) <= 0 {
+			t.Fatalf("releases are not in descending order: %s, %s", releases[i-1].Version, releases[i].Version)
+		}
+	}
+}
+
+func TestRelease_File(t *testing.T) {
+	t.Parallel()
+
+	r := &Release{
+		Version: "go1.21.0",
+		Stable:  true,
+		Files: []File{
+			{Filename: "go1.21.0.src.tar.gz", Kind: FileKindSource},
+			{Filename: "go1.21.0.darwin-arm64.pkg", OS: "darwin", Arch: "arm64", Kind: FileKindInstaller},
+			{Filename: "go1.21.0.darwin-arm64.tar.gz", OS: "darwin", Arch: "arm64", Kind: FileKindArchive},
+			{Filename: "go1.21.0.linux-amd64.tar.gz", OS: "linux", Arch: "amd64", Kind: FileKindArchive},
+		},
+	}
+	for _, i := range []struct {
+		goos, goarch string
+		kind         FileKind
+		filename     string
+	}{
+		{goos: "darwin", goarch: "arm64", kind: FileKindArchive, filename: "go1.21.0.darwin-arm64.tar.gz"},
+		{goos: "darwin", goarch: "arm64", kind: FileKindInstaller, filename: "go1.21.0.darwin-arm64.pkg"},
+		{goos: "linux", goarch: "amd64", kind: FileKindArchive, filename: "go1.21.0.linux-amd64.tar.gz"},
+		{goos: "linux", goarch: "amd64", kind: FileKindSource, filename: "go1.21.0.src.tar.gz"},
+		{goos: "windows", goarch: "amd64", kind: FileKindArchive},
+	} {
+		f, ok := r.File(i.goos, i.goarch, i.kind)
+		if ok != (i.filename != "") || f.Filename != i.filename {
+			t.Errorf("%s/%s %s: unexpected file %q", i.goos, i.goarch, i.kind, f.Filename)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package gocmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// Resolver resolves Go versions and "go" commands with its own catalog of releases, environment of "go" commands
+// and cache of probed commands.
+// The package-level functions use the default Resolver, which is configured by SetReleaseIndex, SetOffline and so on.
+// Each of them has the method of the same name, which works in the same way with the settings of r.
+// Resolvers are independent of each other, so that callers in the same process can use different PATH or catalogs.
+type Resolver struct {
+	catalog *internal.Catalog
+
+	// env is appended to the environment of the process, for "go" subprocesses.
+	env  []string
+	path *string
+	dir  string
+
+	probeMu      sync.Mutex
+	probes       map[string]toolchainEnv
+	dirs         map[string]string
+	requirements map[string]requirementFiles
+
+	searchMu  sync.Mutex
+	locations []SearchLocation
+
+	installedMu sync.Mutex
+	installed   map[string]string
+}
+
+// ResolverOption configures NewResolver.
+type ResolverOption func(r *Resolver)
+
+// WithPATH sets PATH to find "go" commands, instead of PATH of the process.
+// "go" subprocesses run with it too.
+func WithPATH(path string) ResolverOption {
+	return func(r *Resolver) {
+		r.path = &path
+		r.env = append(r.env, "PATH="+path)
+	}
+}
+
+// WithGOTOOLCHAIN sets GOTOOLCHAIN of "go" subprocesses, instead of the environment variable of the process.
+func WithGOTOOLCHAIN(gotoolchain string) ResolverOption {
+	return func(r *Resolver) {
+		r.env = append(r.env, "GOTOOLCHAIN="+gotoolchain)
+	}
+}
+
+// WithDir sets the working directory of "go" subprocesses, instead of the current directory.
+// "go.mod" and "go.work" are found from it.
+func WithDir(dir string) ResolverOption {
+	return func(r *Resolver) {
+		r.dir = dir
+	}
+}
+
+// WithEnv adds environment variables in the form "key=value" to "go" subprocesses.
+// PATH set by it is also used to find "go" commands.
+func WithEnv(env ...string) ResolverOption {
+	return func(r *Resolver) {
+		for _, kv := range env {
+			if k, v, ok := strings.Cut(kv, "="); ok && k == "PATH" {
+				r.path = &v
+			}
+		}
+		r.env = append(r.env, env...)
+	}
+}
+
+// NewResolver returns the Resolver which knows the releases in the embedded list.
+// Its release index is configured by its methods such as SetReleaseIndex, independently of the package-level settings.
+func NewResolver(opts ...ResolverOption) *Resolver {
+	r := &Resolver{
+		catalog:      internal.NewCatalog(),
+		probes:       map[string]toolchainEnv{},
+		dirs:         map[string]string{},
+		requirements: map[string]requirementFiles{},
+		installed:    map[string]string{},
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+var defaultResolver = NewResolver()
+
+// DefaultResolver returns the Resolver used by the package-level functions.
+func DefaultResolver() *Resolver {
+	return defaultResolver
+}
+
+// environ returns the environment of "go" subprocesses with additional variables.
+// It returns nil if the environment of the process is used as it is.
+func (r *Resolver) environ(env []string) []string {
+	if len(r.env) == 0 && len(env) == 0 {
+		return nil
+	}
+	// later values take precedence
+	e := append(os.Environ(), r.env...)
+	return append(e, env...)
+}
+
+// getenv returns the environment variable of r, which is set by WithEnv or the environment of the process.
+func (r *Resolver) getenv(key string) string {
+	for i := len(r.env) - 1; i >= 0; i-- {
+		if k, v, ok := strings.Cut(r.env[i], "="); ok && k == key {
+			return v
+		}
+	}
+	return os.Getenv(key)
+}
+
+// homeDir is like os.UserHomeDir, but the environment variable is read from r.
+func (r *Resolver) homeDir() (string, error) {
+	key := "HOME"
+	switch runtime.GOOS {
+	case "windows":
+		key = "USERPROFILE"
+	case "plan9":
+		key = "home"
+	}
+	if dir := r.getenv(key); dir != "" {
+		return dir, nil
+	}
+	return os.UserHomeDir()
+}
+
+// lookExec finds the executable in PATH of r.
+// Like exec.LookPath, the executable found in a relative directory such as "." is rejected with exec.ErrDot,
+// so that a file in the current directory is never run implicitly.
+func (r *Resolver) lookExec(file string) (string, error) {
+	if r.path == nil || strings.ContainsAny(file, `/\`) {
+		return exec.LookPath(file)
+	}
+	for _, dir := range filepath.SplitList(*r.path) {
+		if dir == "" {
+			dir = "."
+		}
+		path := filepath.Join(dir, file)
+		if !strings.ContainsRune(path, filepath.Separator) {
+			path = "." + string(filepath.Separator) + path
+		}
+		// exec.LookPath checks the path as it is, with PATHEXT on Windows
+		path, err := exec.LookPath(path)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(path) {
+			return path, &exec.Error{Name: file, Err: exec.ErrDot}
+		}
+		return path, nil
+	}
+	return "", &exec.Error{Name: file, Err: exec.ErrNotFound}
+}
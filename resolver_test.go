@@ -0,0 +1,99 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolver(t *testing.T) {
+	t.Parallel()
+
+	for _, version := range []string{"go1.21.5", "go1.22.3"} {
+		t.Run(version, func(t *testing.T) {
+			t.Parallel()
+
+			// each Resolver finds its own "go" in PATH, regardless of PATH of the process
+			dir := filepath.Dir(fakeGo(t, "go", "local", version))
+			r := NewResolver(WithPATH(dir))
+			cur, err := r.CurrentVersion()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cur != version {
+				t.Fatalf("unexpected version: %s", cur)
+			}
+			path, err := r.Lookup(version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != "go" {
+				t.Fatalf("unexpected path: %s", path)
+			}
+			_, err = r.Lookup("go1.20.14")
+			if !errors.Is(err, ErrNotFound) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestResolver_relativePATH(t *testing.T) {
+	t.Parallel()
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := filepath.Rel(wd, filepath.Dir(fakeGo(t, "go", "local", "go1.21.5")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewResolver(WithPATH(dir))
+	_, err = r.lookExec("go")
+	if !errors.Is(err, exec.ErrDot) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	_, err = r.CurrentVersion()
+	if !errors.Is(err, exec.ErrDot) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestResolver_dir(t *testing.T) {
+	t.Parallel()
+
+	r := NewResolver(WithDir("testdata/toolchain"), WithGOTOOLCHAIN("local"))
+	v, err := r.ModuleGoVersionContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.MinimumToolchain != "go1.21.0" || v.Toolchain != "go1.22.3" {
+		t.Fatalf("unexpected version: %+v", v)
+	}
+}
+
+func TestResolver_catalog(t *testing.T) {
+	t.Parallel()
+
+	// the catalog of a Resolver is independent of the default one
+	version := futureVersion(30)
+	path := filepath.Join(t.TempDir(), "releases.json")
+	writeReleaseIndex(t, path, version)
+	r := NewResolver()
+	r.SetIndexCache("off", 0)
+	err := r.SetReleaseIndex(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.ValidVersion(version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := defaultResolver.knownRelease(version); ok {
+		t.Fatalf("%s is known by the default Resolver", version)
+	}
+}
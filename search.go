@@ -0,0 +1,259 @@
+package gocmd
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// SearchLocation is a place where toolchains of specific versions are searched.
+type SearchLocation string
+
+const (
+	// LocationInstalled is the toolchains installed by Install in this process.
+	LocationInstalled SearchLocation = "installed"
+
+	// LocationPATH is the executables named after the version in PATH, such as "go1.21.5" installed by golang.org/dl.
+	// "go" command is also found in PATH.
+	LocationPATH SearchLocation = "PATH"
+
+	// LocationSDK is the SDK directory of golang.org/dl, such as ~/sdk/go1.21.5/bin/go.
+	// The home directory may be set by WithEnv.
+	LocationSDK SearchLocation = "sdk"
+
+	// LocationToolchainCache is the toolchains downloaded by the go command since Go 1.21,
+	// such as $GOMODCACHE/golang.org/toolchain@v0.0.1-go1.21.5.linux-amd64/bin/go.
+	LocationToolchainCache SearchLocation = "toolchain-cache"
+
+	// LocationGOROOT is the toolchain in GOROOT from `go env GOROOT`.
+	LocationGOROOT SearchLocation = "GOROOT"
+
+	// LocationSystem is the toolchains installed in the system, such as /usr/local/go and /usr/lib/go-1.21 of Debian.
+	LocationSystem SearchLocation = "system"
+)
+
+// DefaultSearchLocations returns the search locations used when none is configured, in the order of search.
+func DefaultSearchLocations() []SearchLocation {
+	return []SearchLocation{
+		LocationInstalled,
+		LocationPATH,
+		LocationSDK,
+		LocationToolchainCache,
+		LocationGOROOT,
+		LocationSystem,
+	}
+}
+
+// WithSearchLocations sets the locations to search toolchains, instead of DefaultSearchLocations.
+func WithSearchLocations(locs ...SearchLocation) ResolverOption {
+	return func(r *Resolver) {
+		r.locations = locs
+	}
+}
+
+// SetSearchLocations sets the locations where Lookup, LookupLatest, Determine and so on search toolchains, in the order of search.
+// No locations resets them to DefaultSearchLocations.
+// "go" command in PATH is always checked before them.
+func SetSearchLocations(locs ...SearchLocation) {
+	defaultResolver.SetSearchLocations(locs...)
+}
+
+// SetSearchLocations sets the locations where r searches toolchains, overriding WithSearchLocations.
+func (r *Resolver) SetSearchLocations(locs ...SearchLocation) {
+	r.searchMu.Lock()
+	defer r.searchMu.Unlock()
+	r.locations = locs
+}
+
+// SearchLocations returns the locations to search toolchains, in the order of search.
+func SearchLocations() []SearchLocation {
+	return defaultResolver.SearchLocations()
+}
+
+// SearchLocations returns the locations where r searches toolchains, in the order of search.
+func (r *Resolver) SearchLocations() []SearchLocation {
+	r.searchMu.Lock()
+	defer r.searchMu.Unlock()
+	if len(r.locations) == 0 {
+		return DefaultSearchLocations()
+	}
+	return append([]SearchLocation(nil), r.locations...)
+}
+
+// Command is a "go" command found by Locate, LocateLatest or the DetermineCommand functions.
+type Command struct {
+	// Path is the path of the executable, or "go" for "go" command in PATH.
+	Path string
+
+	// Version is the version of the toolchain.
+	Version string
+
+	// Location is where the command is found.
+	Location SearchLocation
+}
+
+// Locate finds a go executable having exact given version like Lookup, and reports where it is found.
+func Locate(version string) (*Command, error) {
+	return LocateContext(context.Background(), version)
+}
+
+// Locate finds the executable of the given version with PATH and the search locations of r, and reports where it is found.
+func (r *Resolver) Locate(version string) (*Command, error) {
+	return r.LocateContext(context.Background(), version)
+}
+
+// LocateContext is like Locate, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func LocateContext(ctx context.Context, version string) (*Command, error) {
+	return defaultResolver.LocateContext(ctx, version)
+}
+
+// LocateContext is like r.Locate, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) LocateContext(ctx context.Context, version string) (*Command, error) {
+	return r.locate(ctx, version)
+}
+
+// LocateLatest finds a go executable of the latest version in the family of the given version like LookupLatest,
+// and reports where it is found.
+func LocateLatest(version string) (*Command, error) {
+	return LocateLatestContext(context.Background(), version)
+}
+
+// LocateLatest finds the executable of the latest version in the family of the given version with PATH and
+// the search locations of r, and reports where it is found.
+func (r *Resolver) LocateLatest(version string) (*Command, error) {
+	return r.LocateLatestContext(context.Background(), version)
+}
+
+// LocateLatestContext is like LocateLatest, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func LocateLatestContext(ctx context.Context, version string) (*Command, error) {
+	return defaultResolver.LocateLatestContext(ctx, version)
+}
+
+// LocateLatestContext is like r.LocateLatest, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) LocateLatestContext(ctx context.Context, version string) (*Command, error) {
+	err := r.ValidVersionContext(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	return r.lookupLatest(ctx, Version(version).Family(), nil)
+}
+
+// versioned reports whether the toolchains in the location are found by their versions.
+// The toolchain in GOROOT or /usr/local/go is just a candidate, which may have another version.
+func (l SearchLocation) versioned() bool {
+	return l != LocationGOROOT && l != LocationSystem
+}
+
+// find finds the executable of the given version in the search locations, and checks its version.
+func (r *Resolver) find(ctx context.Context, version string) (*Command, error) {
+	var lastErr error
+	for _, c := range r.candidates(ctx, version) {
+		err := r.checkCommandVersion(ctx, c.Path, version)
+		if err == nil {
+			return &c, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if c.Location.versioned() {
+			lastErr = err
+		}
+	}
+	if lastErr == nil {
+		return nil, &exec.Error{Name: version, Err: exec.ErrNotFound}
+	}
+	return nil, lastErr
+}
+
+// candidates returns the executables that may have the given version, in the order of the search locations.
+func (r *Resolver) candidates(ctx context.Context, version string) []Command {
+	var list []Command
+	seen := map[string]bool{}
+	add := func(loc SearchLocation, path string) {
+		// exec.LookPath checks the path as it is, with PATHEXT on Windows
+		path, err := exec.LookPath(path)
+		if err != nil || seen[path] {
+			return
+		}
+		seen[path] = true
+		list = append(list, Command{Path: path, Version: version, Location: loc})
+	}
+	goBin := filepath.Join("bin", "go")
+
+	for _, loc := range r.SearchLocations() {
+		switch loc {
+		case LocationInstalled:
+			r.installedMu.Lock()
+			path, ok := r.installed[version]
+			r.installedMu.Unlock()
+			if ok {
+				add(loc, path)
+			}
+		case LocationPATH:
+			if path, err := r.lookExec(version); err == nil {
+				add(loc, path)
+			}
+		case LocationSDK:
+			if home, err := r.homeDir(); err == nil {
+				add(loc, filepath.Join(home, "sdk", version, goBin))
+			}
+		case LocationToolchainCache:
+			if modCache := r.goDir(ctx, "GOMODCACHE"); modCache != "" {
+				mod := "toolchain@v0.0.1-" + version + "." + runtime.GOOS + "-" + runtime.GOARCH
+				add(loc, filepath.Join(modCache, "golang.org", mod, goBin))
+			}
+		case LocationGOROOT:
+			if goroot := r.goDir(ctx, "GOROOT"); goroot != "" {
+				add(loc, filepath.Join(goroot, goBin))
+			}
+		case LocationSystem:
+			for _, dir := range systemDirs(Version(version)) {
+				add(loc, filepath.Join(dir, goBin))
+			}
+		}
+	}
+	return list
+}
+
+// systemDirs returns the directories where the toolchain of the version may be installed by the system.
+func systemDirs(v Version) []string {
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("ProgramFiles"); dir != "" {
+			return []string{filepath.Join(dir, "Go")}
+		}
+		return nil
+	}
+	dirs := []string{"/usr/local/go"}
+	if v.Family() != "" {
+		// golang-1.21-go of Debian and Ubuntu
+		dirs = append(dirs, "/usr/lib/go-1."+strconv.Itoa(v.Minor()))
+	}
+	return dirs
+}
+
+// goDir returns the directory from `go env {key}`, such as GOROOT and GOMODCACHE.
+// It returns an empty string if "go" command is not available. The result is cached like the probe of commands.
+func (r *Resolver) goDir(ctx context.Context, key string) string {
+	r.probeMu.Lock()
+	dir, ok := r.dirs[key]
+	r.probeMu.Unlock()
+	if ok {
+		return dir
+	}
+	dir, err := r.goEnv(ctx, "go", key, nil)
+	if err != nil && ctx.Err() != nil {
+		// not cached, so that it is retried
+		return ""
+	}
+	r.probeMu.Lock()
+	r.dirs[key] = dir
+	r.probeMu.Unlock()
+	return dir
+}
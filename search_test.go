@@ -0,0 +1,140 @@
+package gocmd
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// placeFakeGo places the fake "go" command of the version at path.
+func placeFakeGo(t *testing.T, path, version string) {
+	t.Helper()
+
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Rename(fakeGo(t, "go", "", version), path)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocate(t *testing.T) {
+	home := t.TempDir()
+	sdk := filepath.Join(home, "sdk", "go1.21.5", "bin", "go")
+	placeFakeGo(t, sdk, "go1.21.5")
+	pathDir := t.TempDir()
+	named := filepath.Join(pathDir, "go1.21.5")
+	placeFakeGo(t, named, "go1.21.5")
+
+	// "go" command of the module cache
+	modCache := t.TempDir()
+	goDir := t.TempDir()
+	script := `#!/bin/sh
+case "$2" in
+GOTOOLCHAIN) echo "local" ;;
+GOVERSION) echo "go1.23.3" ;;
+GOMODCACHE) echo "` + modCache + `" ;;
+esac
+`
+	err := os.WriteFile(filepath.Join(goDir, "go"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mod := "toolchain@v0.0.1-go1.22.3." + runtime.GOOS + "-" + runtime.GOARCH
+	cached := filepath.Join(modCache, "golang.org", mod, "bin", "go")
+	placeFakeGo(t, cached, "go1.22.3")
+	pathList := goDir + string(os.PathListSeparator) + pathDir
+
+	for _, i := range []struct {
+		name      string
+		locations []SearchLocation
+		version   string
+		path      string
+		location  SearchLocation
+	}{
+		{
+			name:     "default",
+			version:  "go1.21.5",
+			path:     named,
+			location: LocationPATH,
+		},
+		{
+			name:      "sdk first",
+			locations: []SearchLocation{LocationSDK, LocationPATH},
+			version:   "go1.21.5",
+			path:      sdk,
+			location:  LocationSDK,
+		},
+		{
+			name:     "toolchain cache",
+			version:  "go1.22.3",
+			path:     cached,
+			location: LocationToolchainCache,
+		},
+		{
+			name:     "go",
+			version:  "go1.23.3",
+			path:     "go",
+			location: LocationPATH,
+		},
+	} {
+		t.Run(i.name, func(t *testing.T) {
+			r := NewResolver(WithPATH(pathList), WithEnv("HOME="+home), WithSearchLocations(i.locations...))
+			c, err := r.Locate(i.version)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Path != i.path || c.Location != i.location || c.Version != i.version {
+				t.Fatalf("unexpected command: %+v", c)
+			}
+
+			// the version is probed, even if the path is not named after it
+			path, ver, err := r.Determine(i.version, ModeExact)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if path != i.path || ver != i.version {
+				t.Fatalf("unexpected command: %s %s", path, ver)
+			}
+			c, err = r.DetermineCommand(i.version, ModeExact)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if c.Path != i.path || c.Location != i.location || c.Version != i.version {
+				t.Fatalf("unexpected command: %+v", c)
+			}
+		})
+	}
+
+	t.Run("latest", func(t *testing.T) {
+		r := NewResolver(WithPATH(pathList), WithEnv("HOME="+home), WithSearchLocations(LocationSDK, LocationPATH))
+		c, err := r.LocateLatest("go1.21.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Path != sdk || c.Location != LocationSDK || c.Version != "go1.21.5" {
+			t.Fatalf("unexpected command: %+v", c)
+		}
+
+		// "go" command is prioritized
+		c, err = r.LocateLatest("go1.23.0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Path != "go" || c.Location != LocationPATH || c.Version != "go1.23.3" {
+			t.Fatalf("unexpected command: %+v", c)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := NewResolver(WithPATH(pathList), WithEnv("HOME="+home), WithSearchLocations(LocationSDK))
+		_, err := r.Locate("go1.22.3")
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,18 @@
+package gocmd
+
+import (
+	"time"
+
+	"github.com/daichitakahashi/gocmd/internal"
+)
+
+// SnapshotTime returns the time when the embedded release index was generated by cmd/genvers.
+func SnapshotTime() time.Time {
+	return internal.SnapshotTime()
+}
+
+// SnapshotAge returns the age of the embedded release index.
+// Tools can warn when they are built with a stale one, because versions released after that require a fetch.
+func SnapshotAge() time.Duration {
+	return time.Since(internal.SnapshotTime())
+}
@@ -0,0 +1,36 @@
+package gocmd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSnapshotTime(t *testing.T) {
+	t.Parallel()
+
+	generated := SnapshotTime()
+	if generated.IsZero() || generated.After(time.Now()) {
+		t.Fatalf("unexpected snapshot time: %s", generated)
+	}
+	if age := SnapshotAge(); age <= 0 {
+		t.Fatalf("unexpected snapshot age: %s", age)
+	}
+}
+
+func TestLatest(t *testing.T) {
+	t.Parallel()
+
+	stable, err := StableVersion(LatestStable.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stable {
+		t.Fatalf("%s is not stable", LatestStable)
+	}
+	if Latest.Compare(LatestStable) < 0 {
+		t.Fatalf("Latest %s is older than LatestStable %s", Latest, LatestStable)
+	}
+	if err := ValidVersion(Go1_9_2rc2.String()); err != nil {
+		t.Fatal(err)
+	}
+}
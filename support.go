@@ -0,0 +1,97 @@
+package gocmd
+
+import (
+	"context"
+	"sort"
+)
+
+// Support is the support status of a Go version.
+// Each major Go release is supported until there are two newer major releases.
+// See https://go.dev/doc/devel/release#policy for details.
+type Support struct {
+	// Version is the version asked for.
+	Version Version
+
+	// Supported reports whether the family of Version is one of the two newest major releases.
+	Supported bool
+
+	// Latest is the latest stable release in the family of Version.
+	// It is an empty string if the family has not been released yet.
+	Latest Version
+
+	// Behind is the number of stable releases in the family newer than Version.
+	// It is 0 if Version is the latest patch release.
+	Behind int
+}
+
+// LatestPatch reports whether Version is the latest patch release of the family.
+func (s *Support) LatestPatch() bool {
+	return s.Latest != "" && s.Version == s.Latest
+}
+
+// SupportStatus returns the support status of the given version, computed from the same source as ValidVersion and StableVersion.
+// A language version since Go 1.21 such as "go1.22", which may come from "go.mod", is treated as its first release "go1.22.0".
+func SupportStatus(version string) (*Support, error) {
+	return SupportStatusContext(context.Background(), version)
+}
+
+// SupportStatus reports the support status of the given version, among the releases known by r.
+func (r *Resolver) SupportStatus(version string) (*Support, error) {
+	return r.SupportStatusContext(context.Background(), version)
+}
+
+// SupportStatusContext is like SupportStatus, but the given context is applied to the fetch of the release index.
+func SupportStatusContext(ctx context.Context, version string) (*Support, error) {
+	return defaultResolver.SupportStatusContext(ctx, version)
+}
+
+// SupportStatusContext is like r.SupportStatus, but the given context is applied to the fetch of the release index.
+func (r *Resolver) SupportStatusContext(ctx context.Context, version string) (*Support, error) {
+	if v := Version(version); v == v.Family() && v.Compare("go1.21") >= 0 {
+		// language version
+		if _, ok := r.knownRelease(version); !ok {
+			version += ".0"
+		}
+	}
+	err := r.ValidVersionContext(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+
+	v := Version(version)
+	s := &Support{
+		Version: v,
+	}
+	var families []Version // families having a stable release, in descending order
+	r.catalog.Versions(func(versions map[string]bool) {
+		seen := map[Version]bool{}
+		for vv, stable := range versions {
+			if !stable {
+				continue
+			}
+			w := Version(vv)
+			if f := w.Family(); !seen[f] {
+				seen[f] = true
+				families = append(families, f)
+			}
+			if !sameFamily(vv, version) {
+				continue
+			}
+			if s.Latest == "" || w.Compare(s.Latest) > 0 {
+				s.Latest = w
+			}
+			if w.Compare(v) > 0 {
+				s.Behind++
+			}
+		}
+	})
+	sort.Slice(families, func(i, j int) bool {
+		return families[i].Compare(families[j]) > 0
+	})
+	for i := 0; i < len(families) && i < 2; i++ {
+		if families[i] == v.Family() {
+			s.Supported = true
+		}
+	}
+	return s, nil
+}
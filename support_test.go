@@ -0,0 +1,65 @@
+package gocmd
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSupportStatus(t *testing.T) {
+	t.Parallel()
+
+	// collect the latest stable release of each family from the catalog
+	var families []Version
+	latest := map[Version]Version{}
+	releases := map[Version][]Version{}
+	for _, r := range Releases() {
+		if !r.Stable {
+			continue
+		}
+		f := r.Version.Family()
+		if _, ok := latest[f]; !ok {
+			families = append(families, f)
+			latest[f] = r.Version
+		}
+		releases[f] = append(releases[f], r.Version)
+	}
+	if len(families) < 3 {
+		t.Fatal("too few families")
+	}
+
+	for _, i := range []struct {
+		version   Version
+		supported bool
+		latest    Version
+		behind    int
+	}{
+		{version: latest[families[0]], supported: true, latest: latest[families[0]]},
+		{version: latest[families[1]], supported: true, latest: latest[families[1]]},
+		{
+			// the first release
+			version:   releases[families[1]][len(releases[families[1]])-1],
+			supported: true,
+			latest:    latest[families[1]],
+			behind:    len(releases[families[1]]) - 1,
+		},
+		{version: latest[families[2]], supported: false, latest: latest[families[2]]},
+		{version: "go1.9.2rc2", supported: false, latest: "go1.9.7", behind: 6},
+		{version: "go1.21", supported: families[1].Compare("go1.21") <= 0, latest: latest["go1.21"], behind: len(releases["go1.21"]) - 1},
+	} {
+		s, err := SupportStatus(string(i.version))
+		if err != nil {
+			t.Fatalf("%s: %s", i.version, err)
+		}
+		if s.Supported != i.supported || s.Latest != i.latest || s.Behind != i.behind {
+			t.Errorf("%s: unexpected status: %#v", i.version, s)
+		}
+		if s.LatestPatch() != (i.behind == 0) {
+			t.Errorf("%s: unexpected LatestPatch: %t", i.version, s.LatestPatch())
+		}
+	}
+
+	_, err := SupportStatus("../invalid")
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
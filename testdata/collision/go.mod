@@ -0,0 +1,4 @@
+// go.mod
+module github.com/daichitakahashi/gocmd
+
+go 1.2
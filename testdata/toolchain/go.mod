@@ -0,0 +1,6 @@
+// go.mod
+module github.com/daichitakahashi/gocmd
+
+go 1.21.0
+
+toolchain go1.22.3
@@ -0,0 +1,10 @@
+// go.mod
+module github.com/daichitakahashi/gocmd
+
+go 1.22.0
+
+toolchain go1.22.5
+
+frobnicate ./internal
+
+require golang.org/x/mod v0.7.0
@@ -0,0 +1,316 @@
+package gocmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ResolvedToolchain is the toolchain that a go command really runs, after GOTOOLCHAIN is interpreted.
+// Since Go 1.21, the go command may switch to another toolchain depending on GOTOOLCHAIN,
+// and the go and toolchain directives in "go.work" or "go.mod".
+// See https://go.dev/doc/toolchain for details.
+type ResolvedToolchain struct {
+	// Command is the go command, such as "go" or the path of specific executable(golang.org/dl/go1.N).
+	Command string
+
+	// GOTOOLCHAIN is the effective value of GOTOOLCHAIN for the command,
+	// which may come from the environment variable, the go env file or "go.env" in GOROOT.
+	// It is an empty string if the command does not support toolchain switching.
+	GOTOOLCHAIN string
+
+	// Local is the version of the toolchain bundled with the command.
+	Local string
+
+	// Version is the version of the toolchain that really runs.
+	Version string
+
+	// Switch reports whether the command switches from the local toolchain to another one.
+	Switch bool
+
+	// Path is the path of the executable that the command switches to, found in PATH.
+	// It is empty if the command does not switch, or the toolchain is going to be downloaded.
+	Path string
+}
+
+// ResolveToolchain resolves the toolchain that "go" command really runs in the current directory.
+// It models the toolchain selection of the go command without running it, so that no toolchain is downloaded.
+// If GOTOOLCHAIN=path (or goX.Y.Z+path) requires a toolchain that is not found in PATH, it returns ErrNotFound.
+// Otherwise, a toolchain not found in PATH is going to be downloaded, so it must be a release known to the release index,
+// or ErrNotFound is returned as the go command fails.
+func ResolveToolchain() (*ResolvedToolchain, error) {
+	return ResolveToolchainContext(context.Background())
+}
+
+// ResolveToolchain resolves the toolchain that "go" command in PATH of r really runs in the working directory of r.
+func (r *Resolver) ResolveToolchain() (*ResolvedToolchain, error) {
+	return r.ResolveToolchainContext(context.Background())
+}
+
+// ResolveToolchainContext is like ResolveToolchain, but the given context is applied to "go env" subprocesses.
+func ResolveToolchainContext(ctx context.Context) (*ResolvedToolchain, error) {
+	return defaultResolver.ResolveToolchainContext(ctx)
+}
+
+// ResolveToolchainContext is like r.ResolveToolchain, but the given context is applied to "go env" subprocesses.
+func (r *Resolver) ResolveToolchainContext(ctx context.Context) (*ResolvedToolchain, error) {
+	return r.resolveToolchain(ctx, "go")
+}
+
+type toolchainEnv struct {
+	gotoolchain string
+	local       string
+}
+
+// commandToolchainEnv returns the GOTOOLCHAIN setting and the version of the local toolchain of cmd.
+// The lock is not held while "go env" runs, so that a slow command does not block probes of others.
+func (r *Resolver) commandToolchainEnv(ctx context.Context, cmd string) (toolchainEnv, error) {
+	r.probeMu.Lock()
+	e, ok := r.probes[cmd]
+	r.probeMu.Unlock()
+	if ok {
+		return e, nil
+	}
+
+	// `go env GOTOOLCHAIN` is always handled by the local toolchain.
+	gotoolchain, err := r.goEnv(ctx, cmd, "GOTOOLCHAIN", nil)
+	if err != nil {
+		return toolchainEnv{}, err
+	}
+	local, err := r.goEnv(ctx, cmd, "GOVERSION", []string{"GOTOOLCHAIN=local"})
+	if err != nil {
+		return toolchainEnv{}, err
+	}
+	e = toolchainEnv{
+		gotoolchain: gotoolchain,
+		local:       local,
+	}
+	r.probeMu.Lock()
+	r.probes[cmd] = e
+	r.probeMu.Unlock()
+	return e, nil
+}
+
+// goEnv runs `{cmd} env {key}` in the environment of r with additional environment variables,
+// and returns the trimmed output.
+// When ctx is done, the process is killed, and the error from ctx is returned.
+// WaitDelay prevents a wrapper script from blocking by its child process holding the output.
+func (r *Resolver) goEnv(ctx context.Context, cmd, key string, env []string) (string, error) {
+	path, err := r.lookExec(cmd)
+	if err != nil {
+		return "", err
+	}
+	c := exec.CommandContext(ctx, path, "env", key)
+	c.Env = r.environ(env)
+	c.Dir = r.dir
+	c.WaitDelay = goEnvWaitDelay
+	out, err := c.Output()
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return "", fmt.Errorf("%s env %s: %w", cmd, key, ctxErr)
+		}
+		return "", err
+	}
+	return string(bytes.TrimSpace(out)), nil
+}
+
+const goEnvWaitDelay = time.Second
+
+func (r *Resolver) resolveToolchain(ctx context.Context, cmd string) (*ResolvedToolchain, error) {
+	e, err := r.commandToolchainEnv(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	t := &ResolvedToolchain{
+		Command:     cmd,
+		GOTOOLCHAIN: e.gotoolchain,
+		Local:       e.local,
+		Version:     e.local,
+	}
+	if e.gotoolchain == "" {
+		// before Go 1.21
+		return t, nil
+	}
+
+	var req *ModuleVersion
+	if strings.HasSuffix(e.gotoolchain, "auto") || strings.HasSuffix(e.gotoolchain, "path") {
+		req = r.toolchainRequirement(ctx)
+	}
+	name, mode, err := selectToolchain(e.gotoolchain, e.local, req)
+	if err != nil {
+		return nil, err
+	}
+	if name == "local" || name == e.local || Version(name) == localVersion(e.local) {
+		return t, nil
+	}
+
+	t.Version = name
+	t.Switch = true
+	t.Path, err = r.lookExec(name)
+	if err == nil {
+		return t, nil
+	}
+	t.Path = ""
+	if mode == "path" {
+		return nil, fmt.Errorf("GOTOOLCHAIN=%s: cannot find %q in PATH: %w", e.gotoolchain, name, ErrNotFound)
+	}
+	// the go command downloads the toolchain, which fails unless it is a release
+	if _, err := r.lookupVersion(ctx, name); err != nil {
+		if errors.Is(err, ErrInvalidVersion) {
+			return nil, fmt.Errorf("GOTOOLCHAIN=%s: toolchain %q is neither in PATH nor released: %w", e.gotoolchain, name, ErrNotFound)
+		}
+		return nil, err
+	}
+	return t, nil
+}
+
+// toolchainRequirement reads the go and toolchain directives from "go.work" in workspace mode, or "go.mod".
+// Like the go command, unreadable files are just ignored here.
+func (r *Resolver) toolchainRequirement(ctx context.Context) *ModuleVersion {
+	files, err := r.requirementFiles(ctx)
+	if err != nil {
+		return nil
+	}
+	if files.work != "" {
+		work, err := readWorkGoVersion(files.work)
+		if err != nil {
+			return nil
+		}
+		return &work.ModuleVersion
+	}
+	if files.mod == "" {
+		return nil
+	}
+	ver, err := readModuleGoVersion(files.mod)
+	if err != nil {
+		return nil
+	}
+	return &ver
+}
+
+// requirementFiles is the paths of "go.work" and "go.mod" from `go env GOWORK` and `go env GOMOD`.
+// They are empty if the files are not used.
+type requirementFiles struct {
+	work string
+	mod  string
+}
+
+// requirementFiles returns the paths of the files which have the requirement of toolchains.
+// They are cached for each working directory and environment, because every probe of commands needs them.
+// The files themselves are read every time, so that changes of them are reflected.
+func (r *Resolver) requirementFiles(ctx context.Context) (requirementFiles, error) {
+	key, err := r.requirementKey()
+	if err != nil {
+		return requirementFiles{}, err
+	}
+	r.probeMu.Lock()
+	files, ok := r.requirements[key]
+	r.probeMu.Unlock()
+	if ok {
+		return files, nil
+	}
+
+	files.work, err = r.goWorkPath(ctx)
+	if err != nil {
+		return requirementFiles{}, err
+	}
+	if files.work == "" {
+		files.mod, err = r.goModPath(ctx)
+		if errors.Is(err, fs.ErrNotExist) {
+			files.mod = ""
+		} else if err != nil {
+			return requirementFiles{}, err
+		}
+	}
+	r.probeMu.Lock()
+	r.requirements[key] = files
+	r.probeMu.Unlock()
+	return files, nil
+}
+
+// requirementKey returns the key of the working directory and the environment of "go" subprocesses.
+func (r *Resolver) requirementKey() (string, error) {
+	dir, err := filepath.Abs(r.dir)
+	if err != nil {
+		return "", err
+	}
+	env := r.environ(nil)
+	if env == nil {
+		env = os.Environ()
+	}
+	return dir + "\x00" + strings.Join(env, "\x00"), nil
+}
+
+// selectToolchain interprets GOTOOLCHAIN in the same way as the go command, and returns the name of the toolchain to run
+// and the mode ("auto", "path" or empty).
+// The name is "local" or the version of the local toolchain if the command does not switch.
+// req is the requirement read from "go.work" or "go.mod", which is nil if there is none.
+// Note that "toolchain default" in the file is treated as if the toolchain directive is omitted.
+func selectToolchain(gotoolchain, local string, req *ModuleVersion) (name, mode string, _ error) {
+	minToolchain := "local"
+	minVers := localVersion(local)
+	switch gotoolchain {
+	case "auto", "path":
+		mode = gotoolchain
+	case "local":
+		return "local", "", nil
+	default:
+		min, suffix, plus := strings.Cut(gotoolchain, "+") // go1.2.3+auto
+		if min != "local" {
+			v := toolchainVersion(min)
+			if v == "" {
+				return "", "", fmt.Errorf("invalid GOTOOLCHAIN %q", gotoolchain)
+			}
+			minToolchain = min
+			minVers = v
+		}
+		if plus && suffix != "auto" && suffix != "path" {
+			return "", "", fmt.Errorf("invalid GOTOOLCHAIN %q: only version suffixes are +auto and +path", gotoolchain)
+		}
+		mode = suffix
+	}
+
+	name = minToolchain
+	if (mode == "auto" || mode == "path") && req != nil {
+		if req.Toolchain != "" && req.Toolchain.Compare(minVers) > 0 {
+			name = string(req.Toolchain)
+			minVers = req.Toolchain
+		}
+		if !atLeast(minVers, req.MinimumToolchain) {
+			name = string(req.MinimumToolchain)
+			minVers = req.MinimumToolchain
+			// the first release of the language version since Go 1.21 is "go1.N.0"
+			if req.MinimumToolchain == req.Language && req.Language.Compare("go1.21") >= 0 {
+				name += ".0"
+			}
+		}
+	}
+	return name, mode, nil
+}
+
+// toolchainVersion returns the version of toolchain name such as "go1.21.3" or "go1.21.3-bigcorp".
+// It returns an empty string if the name is invalid.
+func toolchainVersion(name string) Version {
+	name, _, _ = strings.Cut(name, "-")
+	v, err := ParseVersion(name)
+	if err != nil {
+		return ""
+	}
+	return v
+}
+
+// localVersion returns the version from GOVERSION such as "go1.21.3 X:boringcrypto".
+func localVersion(goversion string) Version {
+	f := strings.Fields(goversion)
+	if len(f) == 0 {
+		return ""
+	}
+	return toolchainVersion(f[0])
+}
@@ -0,0 +1,249 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSelectToolchain(t *testing.T) {
+	t.Parallel()
+
+	legacy := &ModuleVersion{Language: "go1.19", MinimumToolchain: "go1.19"}
+	lang := &ModuleVersion{Language: "go1.22", MinimumToolchain: "go1.22"}
+	withToolchain := &ModuleVersion{Language: "go1.21", MinimumToolchain: "go1.21.0", Toolchain: "go1.22.3"}
+
+	for _, i := range []struct {
+		gotoolchain string
+		req         *ModuleVersion
+		name, mode  string
+		error       bool
+	}{
+		{gotoolchain: "local", req: withToolchain, name: "local"},
+		{gotoolchain: "auto", name: "local", mode: "auto"},
+		{gotoolchain: "auto", req: legacy, name: "local", mode: "auto"},
+		{gotoolchain: "auto", req: lang, name: "go1.22.0", mode: "auto"},
+		{gotoolchain: "path", req: withToolchain, name: "go1.22.3", mode: "path"},
+		{gotoolchain: "go1.23.1", req: withToolchain, name: "go1.23.1"},
+		{gotoolchain: "go1.22.1+auto", req: withToolchain, name: "go1.22.3", mode: "auto"},
+		{gotoolchain: "go1.23.1+auto", req: withToolchain, name: "go1.23.1", mode: "auto"},
+		{gotoolchain: "go1.21.2-bigcorp+path", req: legacy, name: "go1.21.2-bigcorp", mode: "path"},
+		{gotoolchain: "local+auto", req: lang, name: "go1.22.0", mode: "auto"},
+		{gotoolchain: "go1.23.1+local", error: true},
+		{gotoolchain: "bash", error: true},
+	} {
+		name, mode, err := selectToolchain(i.gotoolchain, "go1.21.5", i.req)
+		if i.error {
+			if err == nil {
+				t.Errorf("%s: error expected", i.gotoolchain)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: %s", i.gotoolchain, err)
+		}
+		if name != i.name || mode != i.mode {
+			t.Errorf("%s: unexpected result: name=%q, mode=%q", i.gotoolchain, name, mode)
+		}
+	}
+}
+
+// fakeGo creates an executable that reports the given GOTOOLCHAIN and GOVERSION.
+func fakeGo(t *testing.T, name, gotoolchain, goversion string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("test skipped because shell script is not available")
+	}
+
+	path := filepath.Join(t.TempDir(), name)
+	script := `#!/bin/sh
+case "$2" in
+GOTOOLCHAIN) echo "` + gotoolchain + `" ;;
+GOVERSION) echo "` + goversion + `" ;;
+esac
+`
+	err := os.WriteFile(path, []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveToolchain(t *testing.T) {
+	t.Run("switch", func(t *testing.T) {
+		// go.mod declares "go 1.21.0" and "toolchain go1.22.3"
+		r := NewResolver(WithDir("testdata/toolchain"))
+		cmd := fakeGo(t, "go", "auto", "go1.21.0")
+
+		got, err := r.resolveToolchain(context.Background(), cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		diff := cmp.Diff(&ResolvedToolchain{
+			Command:     cmd,
+			GOTOOLCHAIN: "auto",
+			Local:       "go1.21.0",
+			Version:     "go1.22.3",
+			Switch:      true,
+		}, got)
+		if diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("switch with PATH", func(t *testing.T) {
+		cmd := fakeGo(t, "go", "path", "go1.21.0")
+		target := fakeGo(t, "go1.22.3", "path", "go1.22.3")
+		goroot := filepath.Dir(mustLookPath(t, "go"))
+		r := NewResolver(
+			WithDir("testdata/toolchain"),
+			WithPATH(filepath.Dir(target)+string(os.PathListSeparator)+goroot),
+		)
+
+		got, err := r.resolveToolchain(context.Background(), cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !got.Switch || got.Version != "go1.22.3" || got.Path != target {
+			t.Fatalf("unexpected result: %#v", got)
+		}
+	})
+
+	t.Run("not found in PATH", func(t *testing.T) {
+		cmd := fakeGo(t, "go", "go1.22.3+path", "go1.21.0")
+		goroot := filepath.Dir(mustLookPath(t, "go"))
+		r := NewResolver(WithDir("testdata/toolchain"), WithPATH(goroot))
+
+		_, err := r.resolveToolchain(context.Background(), cmd)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("not released", func(t *testing.T) {
+		// go.mod declares "go 1.99"
+		cmd := fakeGo(t, "go", "auto", "go1.21.0")
+		index := filepath.Join(t.TempDir(), "index.json")
+		writeReleaseIndex(t, index, futureVersion(1))
+		r := newIndexResolver(t, index, WithDir("testdata/future"))
+
+		_, err := r.resolveToolchain(context.Background(), cmd)
+		if !errors.Is(err, ErrNotFound) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("local", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/toolchain"))
+		cmd := fakeGo(t, "go", "local", "go1.21.0")
+
+		got, err := r.resolveToolchain(context.Background(), cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Switch || got.Version != "go1.21.0" {
+			t.Fatalf("unexpected result: %#v", got)
+		}
+	})
+
+	t.Run("before go1.21", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/toolchain"))
+		cmd := fakeGo(t, "go", "", "go1.20.14")
+
+		got, err := r.resolveToolchain(context.Background(), cmd)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Switch || got.Version != "go1.20.14" {
+			t.Fatalf("unexpected result: %#v", got)
+		}
+	})
+}
+
+func mustLookPath(t *testing.T, file string) string {
+	t.Helper()
+
+	path, err := exec.LookPath(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestResolveToolchain_deadline(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test skipped because shell script is not available")
+	}
+	t.Parallel()
+
+	// hung wrapper, whose child process holds the output
+	cmd := filepath.Join(t.TempDir(), "go")
+	err := os.WriteFile(cmd, []byte("#!/bin/sh\nsleep 30\n"), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = NewResolver().resolveToolchain(ctx, cmd)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 10*time.Second {
+		t.Fatalf("too slow to cancel: %s", elapsed)
+	}
+}
+
+func TestResolveToolchain_requirementCache(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test skipped because shell script is not available")
+	}
+	t.Parallel()
+
+	// go.mod declares "go 1.21.0" and "toolchain go1.22.3"
+	gomod, err := filepath.Abs(filepath.Join("testdata", "toolchain", "go.mod"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	log := filepath.Join(dir, "log")
+	script := `#!/bin/sh
+echo "$2" >> ` + log + `
+case "$2" in
+GOTOOLCHAIN) echo auto ;;
+GOVERSION) echo go1.21.0 ;;
+GOMOD) echo ` + gomod + ` ;;
+esac
+`
+	err = os.WriteFile(filepath.Join(dir, "go"), []byte(script), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewResolver(WithPATH(dir))
+	for i := 0; i < 3; i++ {
+		ver, err := r.commandVersion(context.Background(), "go")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ver != "go1.22.3" {
+			t.Fatalf("unexpected version: %s", ver)
+		}
+	}
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "GOTOOLCHAIN\nGOVERSION\nGOWORK\nGOMOD\n"
+	if string(data) != want {
+		t.Fatalf("unexpected go env calls: want %q, got %q", want, data)
+	}
+}
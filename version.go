@@ -1,17 +1,12 @@
 package gocmd
 
 import (
-	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"os/exec"
-	"path/filepath"
-	"regexp"
 	"sort"
-	"strings"
 	"sync"
-
-	"github.com/daichitakahashi/gocmd/internal"
 )
 
 var ErrInvalidVersion = errors.New("invalid version")
@@ -20,102 +15,159 @@ var ErrInvalidVersion = errors.New("invalid version")
 // The source of correctness is the following URL:
 //
 //	https://go.dev/dl/?mode=json&include=all
+//
+// The source can be replaced with a mirror by SetReleaseIndex.
+// The release index is fetched only when the version is well-formed and newer than the newest known release.
 func ValidVersion(version string) error {
-	// handle non-version string as error
-	if filepath.Base(version) != version {
-		return ErrInvalidVersion
-	}
+	return ValidVersionContext(context.Background(), version)
+}
 
-	var ok bool
-	internal.Versions(func(versions map[string]bool) {
-		_, ok = versions[version]
-	})
-	if ok {
-		return nil
-	}
-	fetched, err := internal.FetchOnce()
-	if err != nil {
-		return err
-	}
-	if fetched {
-		internal.Versions(func(versions map[string]bool) {
-			_, ok = versions[version]
-		})
-		if ok {
-			return nil
-		}
-	}
-	return ErrInvalidVersion
+// ValidVersion checks the version against the releases known by r, fetching the release index of r if needed.
+func (r *Resolver) ValidVersion(version string) error {
+	return r.ValidVersionContext(context.Background(), version)
+}
 
+// ValidVersionContext is like ValidVersion, but the given context is applied to the fetch of the release index.
+func ValidVersionContext(ctx context.Context, version string) error {
+	return defaultResolver.ValidVersionContext(ctx, version)
+}
+
+// ValidVersionContext is like r.ValidVersion, but the given context is applied to the fetch of the release index.
+func (r *Resolver) ValidVersionContext(ctx context.Context, version string) error {
+	_, err := r.lookupVersion(ctx, version)
+	return err
 }
 
 // StableVersion returns whether the given go version exists and is stable.
 // The source of correctness is the following URL:
 //
 //	https://go.dev/dl/?mode=json&include=all
+//
+// The source can be replaced with a mirror by SetReleaseIndex.
 func StableVersion(version string) (bool, error) {
-	// handle non-version string as error
-	if filepath.Base(version) != version {
-		return false, ErrInvalidVersion
+	return StableVersionContext(context.Background(), version)
+}
+
+// StableVersion reports whether the version is a stable release known by r.
+func (r *Resolver) StableVersion(version string) (bool, error) {
+	return r.StableVersionContext(context.Background(), version)
+}
+
+// StableVersionContext is like StableVersion, but the given context is applied to the fetch of the release index.
+func StableVersionContext(ctx context.Context, version string) (bool, error) {
+	return defaultResolver.StableVersionContext(ctx, version)
+}
+
+// StableVersionContext is like r.StableVersion, but the given context is applied to the fetch of the release index.
+func (r *Resolver) StableVersionContext(ctx context.Context, version string) (bool, error) {
+	return r.lookupVersion(ctx, version)
+}
+
+// lookupVersion returns whether the given version is stable, or an error if it does not exist.
+// The release index is fetched only for a well-formed version newer than the newest known release,
+// so that malformed input such as "go1.19x" never costs a network round trip.
+// Versions not found in the fetched index are cached negatively.
+func (r *Resolver) lookupVersion(ctx context.Context, version string) (bool, error) {
+	v, err := ParseVersion(version)
+	if err != nil {
+		return false, err
 	}
 
 	var stable, ok bool
-	internal.Versions(func(versions map[string]bool) {
+	var newestFamily, newestInFamily Version
+	family := v.Family()
+	r.catalog.Versions(func(versions map[string]bool) {
 		stable, ok = versions[version]
+		if ok {
+			return
+		}
+		for vv := range versions {
+			w := Version(vv)
+			if f := w.Family(); f.Compare(newestFamily) > 0 {
+				newestFamily = f
+			}
+			if w.Family() == family && w.Compare(newestInFamily) > 0 {
+				newestInFamily = w
+			}
+		}
 	})
 	if ok {
 		return stable, nil
 	}
-	fetched, err := internal.FetchOnce()
+	// Patch releases of the previous family ship together with the current one,
+	// so the known releases are complete only up to the newest one of each family.
+	newer := family.Compare(newestFamily) > 0 || (newestInFamily != "" && v.Compare(newestInFamily) > 0)
+	if !newer || r.catalog.Unknown(version) {
+		return false, ErrInvalidVersion
+	}
+
+	fetched, err := r.fetchOnce(ctx)
 	if err != nil {
 		return false, err
 	}
 	if fetched {
-		internal.Versions(func(versions map[string]bool) {
+		r.catalog.Versions(func(versions map[string]bool) {
 			stable, ok = versions[version]
 		})
 		if ok {
 			return stable, nil
 		}
 	}
+	if r.catalog.Offline() {
+		return false, ErrUnknownOffline
+	}
+	r.catalog.MarkUnknown(version)
 	return false, ErrInvalidVersion
 }
 
-var (
-	verCache = map[string]string{}
-	vm       sync.Mutex
-)
-
-func commandVersion(cmd string) (string, error) {
-	vm.Lock()
-	defer vm.Unlock()
-	if v, ok := verCache[cmd]; ok {
-		return v, nil
-	}
-	gotVersion, err := exec.Command(cmd, "env", "GOVERSION").Output()
+// commandVersion returns the version of the toolchain that cmd really runs.
+func (r *Resolver) commandVersion(ctx context.Context, cmd string) (string, error) {
+	t, err := r.resolveToolchain(ctx, cmd)
 	if err != nil {
 		return "", err
 	}
-	v := string(bytes.TrimSpace(gotVersion))
-	verCache[cmd] = v
-	return v, nil
+	return t.Version, nil
 }
 
 // CurrentVersion returns the version of "go" command.
+// If "go" command switches to another toolchain by GOTOOLCHAIN, it returns the version of the toolchain that really runs.
+// See ResolveToolchain for details.
 func CurrentVersion() (string, error) {
-	return commandVersion("go")
+	return CurrentVersionContext(context.Background())
+}
+
+// CurrentVersion returns the version of the toolchain that "go" command in PATH of r really runs.
+func (r *Resolver) CurrentVersion() (string, error) {
+	return r.CurrentVersionContext(context.Background())
+}
+
+// CurrentVersionContext is like CurrentVersion, but the given context is applied to "go env" subprocesses.
+func CurrentVersionContext(ctx context.Context) (string, error) {
+	return defaultResolver.CurrentVersionContext(ctx)
+}
+
+// CurrentVersionContext is like r.CurrentVersion, but the given context is applied to "go env" subprocesses.
+func (r *Resolver) CurrentVersionContext(ctx context.Context) (string, error) {
+	return r.commandVersion(ctx, "go")
 }
 
 // MajorVersion returns major version of the given version.
 // If the given version is invalid, returned value is an empty string.
 func MajorVersion(version string) string {
-	return versionRe.FindString(version)
+	return string(Version(version).Family())
+}
+
+// sameFamily reports whether both versions are well-formed and belong to the same release family.
+// Unlike comparing by prefix, "go1.2" and "go1.21" are different families.
+func sameFamily(v, w string) bool {
+	f := Version(v).Family()
+	return f != "" && f == Version(w).Family()
 }
 
 var ErrNotFound = exec.ErrNotFound
 
-func checkCommandVersion(cmd, version string) error {
-	gotVersion, err := commandVersion(cmd)
+func (r *Resolver) checkCommandVersion(ctx context.Context, cmd, version string) error {
+	gotVersion, err := r.commandVersion(ctx, cmd)
 	if err != nil {
 		return err
 	}
@@ -128,213 +180,448 @@ func checkCommandVersion(cmd, version string) error {
 // Lookup finds a go executable having exact given version.
 // Firstly, it checks the given version with ValidVersion, and returns ErrInvalidVersion if the version is invalid.
 // After that, it checks versions of "go" and specific executable(golang.org/dl/go1.N).
+// The specific executable is searched in the locations set by SetSearchLocations, such as PATH and ~/sdk.
 // When an executable with GOVERSION={given version} exists, it returns the executable's path.
 // If no executable exists, it returns ErrNotFound.
 func Lookup(version string) (string, error) {
-	err := ValidVersion(version)
+	return LookupContext(context.Background(), version)
+}
+
+// Lookup finds the executable of the given version with PATH and the search locations of r.
+func (r *Resolver) Lookup(version string) (string, error) {
+	return r.LookupContext(context.Background(), version)
+}
+
+// LookupContext is like Lookup, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func LookupContext(ctx context.Context, version string) (string, error) {
+	return defaultResolver.LookupContext(ctx, version)
+}
+
+// LookupContext is like r.Lookup, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) LookupContext(ctx context.Context, version string) (string, error) {
+	c, err := r.locate(ctx, version)
 	if err != nil {
 		return "", err
 	}
+	return c.Path, nil
+}
+
+// locate finds "go" command or the executable in the search locations, having exact given version.
+func (r *Resolver) locate(ctx context.Context, version string) (*Command, error) {
+	err := r.ValidVersionContext(ctx, version)
+	if err != nil {
+		return nil, err
+	}
 
-	var full string
+	var found *Command
 	var goErr, verErr error
 	var wg sync.WaitGroup
 	wg.Add(2)
 	go func() {
 		defer wg.Done()
-		goErr = checkCommandVersion("go", version)
+		goErr = r.checkCommandVersion(ctx, "go", version)
 	}()
 	go func() {
 		defer wg.Done()
-		full, verErr = exec.LookPath(version)
-		if verErr != nil {
-			return
-		}
-		verErr = checkCommandVersion(full, version)
+		found, verErr = r.find(ctx, version)
 	}()
 	wg.Wait()
 
 	if goErr == nil {
-		return "go", nil
+		return &Command{Path: "go", Version: version, Location: LocationPATH}, nil
 	}
 	if verErr == nil {
-		return full, nil
+		return found, nil
 	}
 
 	if errors.Is(verErr, exec.ErrNotFound) {
-		return "", ErrNotFound
+		return nil, ErrNotFound
 	}
-	return "", verErr
+	return nil, verErr
 }
 
-var versionRe = regexp.MustCompile(`^go[1-9][0-9]*\.(?:0|[1-9][0-9]*)`)
-
 // LookupLatest finds a go executable having the given version.
 // Behavior is similar to Lookup, but it collects versions that have the same major version.
 // This finds the executable that has the latest version in the collected list.
 // If "go" command has the same major version, it is prioritized.
 func LookupLatest(version string) (string, error) {
-	err := ValidVersion(version)
+	return LookupLatestContext(context.Background(), version)
+}
+
+// LookupLatest finds the executable of the latest version in the family of the given version, with PATH and the search locations of r.
+func (r *Resolver) LookupLatest(version string) (string, error) {
+	return r.LookupLatestContext(context.Background(), version)
+}
+
+// LookupLatestContext is like LookupLatest, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func LookupLatestContext(ctx context.Context, version string) (string, error) {
+	return defaultResolver.LookupLatestContext(ctx, version)
+}
+
+// LookupLatestContext is like r.LookupLatest, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) LookupLatestContext(ctx context.Context, version string) (string, error) {
+	c, err := r.LocateLatestContext(ctx, version)
 	if err != nil {
 		return "", err
 	}
+	return c.Path, nil
+}
 
-	expectedVer := versionRe.FindString(version)
+// lookupLatest finds the executable that has the latest version in the given family.
+// If accept is not nil, versions not accepted by it are skipped.
+// If "go" command is acceptable, it is prioritized.
+func (r *Resolver) lookupLatest(ctx context.Context, family Version, accept func(Version) bool) (*Command, error) {
+	acceptable := func(v string) bool {
+		return sameFamily(v, string(family)) && (accept == nil || accept(Version(v)))
+	}
 
 	// check "go" command
-	cur, err := CurrentVersion()
+	cur, err := r.CurrentVersionContext(ctx)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	if strings.HasPrefix(cur, expectedVer) {
-		return "go", nil
+	if acceptable(cur) {
+		return goCommand(cur), nil
 	}
 
-	// find the latest command
-	for _, c := range findCandidates(expectedVer) {
-		full, err := exec.LookPath(c)
+	candidates := r.findCandidates(string(family))
+	if len(candidates) == 0 {
+		// the family may be newer than known versions
+		fetched, err := r.fetchOnce(ctx)
 		if err != nil {
+			return nil, err
+		}
+		if fetched {
+			candidates = r.findCandidates(string(family))
+		}
+	}
+
+	// find the latest command
+	for _, c := range candidates {
+		if !acceptable(c) {
 			continue
 		}
-		err = checkCommandVersion(full, c)
+		found, err := r.find(ctx, c)
 		if err == nil {
-			return full, nil
+			return found, nil
 		}
 	}
-	return "", ErrNotFound
+	return nil, ErrNotFound
 }
 
-// this function must be called after internal.FetchAllVersions
-func findCandidates(expectedVer string) []string {
-	v := &byLatestGoVersion{
-		expectedVer: expectedVer,
-	}
-	internal.Versions(func(versions map[string]bool) {
+// goCommand returns "go" command in PATH, which has the version.
+func goCommand(version string) *Command {
+	return &Command{Path: "go", Version: version, Location: LocationPATH}
+}
+
+// findCandidates returns the versions in the same family as expectedVer, known to the catalog of r, in descending order.
+// It does not fetch the release index, so the caller fetches it by r.fetchOnce if no candidate is known.
+func (r *Resolver) findCandidates(expectedVer string) []string {
+	var candidates []string
+	r.catalog.Versions(func(versions map[string]bool) {
 		for vv := range versions {
-			if strings.HasPrefix(vv, expectedVer) {
-				v.versions = append(v.versions, vv)
+			if sameFamily(vv, expectedVer) {
+				candidates = append(candidates, vv)
 			}
 		}
 	})
-	sort.Sort(v)
-	return v.versions
+	// sort in descending order
+	sort.Slice(candidates, func(i, j int) bool {
+		return Version(candidates[i]).Compare(Version(candidates[j])) > 0
+	})
+	return candidates
 }
 
-// implements sort.Interface.
-// It sorts Go versions in descending order.
-type byLatestGoVersion struct {
-	expectedVer string
-	versions    []string
+// Determine go command with given version, and return its path and actual version.
+// Following modes are available, and they can be combined. See Mode for the order of strategies.
+//   - ModeExact determines command by using Lookup
+//   - ModeLatest determines command by using LookupLatest
+//   - ModeMinimum determines the oldest installed command whose version is the given version or later
+//   - ModeNewest determines the newest installed command whose version is the given version or later
+//   - ModeFallback fallbacks to "go" command, if no command was found by the other strategies
+//   - ModeStable excludes betas and release candidates from ModeLatest, ModeMinimum and ModeNewest
+//
+// ModeFallback alone behaves as ModeLatest|ModeFallback, and zero Mode too.
+// If the combination makes no sense, it returns ErrInvalidMode.
+func Determine(version string, mode Mode) (path, ver string, err error) {
+	return DetermineContext(context.Background(), version, mode)
 }
 
-func (b *byLatestGoVersion) Len() int {
-	return len(b.versions)
+// Determine determines the command with the strategies of mode, among the commands found by r.
+func (r *Resolver) Determine(version string, mode Mode) (path, ver string, err error) {
+	return r.DetermineContext(context.Background(), version, mode)
 }
 
-type typ int
+// DetermineContext is like Determine, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func DetermineContext(ctx context.Context, version string, mode Mode) (path, ver string, err error) {
+	return defaultResolver.DetermineContext(ctx, version, mode)
+}
 
-const (
-	beta typ = iota + 1
-	rc
-	stable
-)
+// DetermineContext is like r.Determine, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) DetermineContext(ctx context.Context, version string, mode Mode) (path, ver string, err error) {
+	return commandResult(r.DetermineCommandContext(ctx, version, mode))
+}
 
-func getTyp(s string) typ {
-	if s == "" {
-		return stable
-	} else if strings.HasPrefix(s, "beta") {
-		return beta
-	} else if strings.HasPrefix(s, "rc") {
-		return rc
-	}
-	return stable
+// DetermineCommand is like Determine, but reports where the command is found.
+func DetermineCommand(version string, mode Mode) (*Command, error) {
+	return DetermineCommandContext(context.Background(), version, mode)
 }
 
-func (b *byLatestGoVersion) Less(i, j int) bool {
-	iv, jv := b.versions[i], b.versions[j]
+// DetermineCommand is like r.Determine, but reports where the command is found.
+func (r *Resolver) DetermineCommand(version string, mode Mode) (*Command, error) {
+	return r.DetermineCommandContext(context.Background(), version, mode)
+}
 
-	var it, jt typ
-	it = getTyp(strings.TrimPrefix(iv, b.expectedVer))
-	jt = getTyp(strings.TrimPrefix(jv, b.expectedVer))
+// DetermineCommandContext is like DetermineCommand, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func DetermineCommandContext(ctx context.Context, version string, mode Mode) (*Command, error) {
+	return defaultResolver.DetermineCommandContext(ctx, version, mode)
+}
 
-	// compare typ
-	if it != jt {
-		return it > jt
+// DetermineCommandContext is like r.DetermineCommand, but the given context is applied to the fetch of the release index
+// and every "go env" subprocess.
+func (r *Resolver) DetermineCommandContext(ctx context.Context, version string, mode Mode) (*Command, error) {
+	mode, err := mode.normalize(ModeLatest | ModeFallback)
+	if err != nil {
+		return nil, err
 	}
 
-	// compare string
-	il, jl := len(iv), len(jv)
-	if il != jl {
-		return il > jl
+	var c *Command
+	var errs []error
+	if mode&ModeExact != 0 {
+		c, err = r.locate(ctx, version)
+		if err == nil {
+			return r.determined(ctx, c)
+		}
+		errs = append(errs, fmt.Errorf(`failed to find "go" command which has the version %s exactly: %w`, version, err))
+	}
+	if mode&ModeLatest != 0 {
+		if err = r.ValidVersionContext(ctx, version); err == nil {
+			c, err = r.lookupLatest(ctx, Version(version).Family(), mode.accept(nil))
+		}
+		if err == nil {
+			return r.determined(ctx, c)
+		}
+		errs = append(errs, fmt.Errorf(`failed to find "go" command that has major version %s: %w`, MajorVersion(version), err))
+	}
+	if mode&(ModeMinimum|ModeNewest) != 0 {
+		if err = r.ValidVersionContext(ctx, version); err == nil {
+			c, err = r.lookupInstalled(ctx, mode.accept(func(v Version) bool {
+				return v.Compare(Version(version)) >= 0
+			}), mode&ModeNewest != 0)
+		}
+		if err == nil {
+			return r.determined(ctx, c)
+		}
+		errs = append(errs, fmt.Errorf(`failed to find "go" command whose version is %s or later: %w`, version, err))
+	}
+	if mode&ModeFallback != 0 {
+		return r.determined(ctx, goCommand(""))
 	}
-	return iv > jv
+	return nil, errors.Join(errs...)
 }
 
-func (b *byLatestGoVersion) Swap(i, j int) {
-	b.versions[i], b.versions[j] = b.versions[j], b.versions[i]
+// determined returns the determined command with its actual version.
+// The version is probed, because the path such as ~/sdk/go1.21.5/bin/go may not be named after the version.
+func (r *Resolver) determined(ctx context.Context, c *Command) (*Command, error) {
+	ver, err := r.commandVersion(ctx, c.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %q version: %w", c.Path, err)
+	}
+	return &Command{Path: c.Path, Version: ver, Location: c.Location}, nil
 }
 
-var _ sort.Interface = (*byLatestGoVersion)(nil)
-
-type Mode uint8
-
-const (
-	ModeExact Mode = 1 << iota
-	ModeLatest
-	ModeFallback
-)
+// commandResult returns the path and the version of the command, for the functions which do not report the location.
+func commandResult(c *Command, err error) (path, ver string, _ error) {
+	if err != nil {
+		return "", "", err
+	}
+	return c.Path, c.Version, nil
+}
 
-// Determine go command with given version, and return its path and actual version.
-// Following mode is available.
-//   - ModeExact determines command by using Lookup
-//   - ModeLatest determines command by using LookupLatest
-//   - ModeFallback determines command by using LookupLatest, but if no command was found, fallbacks to "go" command
-func Determine(version string, mode Mode) (path, ver string, err error) {
-	if mode == ModeExact {
-		path, err = Lookup(version)
+// lookupInstalled finds the oldest executable whose version is accepted, regardless of its family.
+// If newest is true, it finds the newest one instead.
+// "go" command takes part in the comparison by its version, and it is prioritized over the same version.
+func (r *Resolver) lookupInstalled(ctx context.Context, accept func(Version) bool, newest bool) (*Command, error) {
+	cur, err := r.CurrentVersionContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	goOK := accept(Version(cur))
+
+	var candidates []string
+	collect := func() {
+		candidates = candidates[:0]
+		r.catalog.Versions(func(versions map[string]bool) {
+			for v := range versions {
+				if accept(Version(v)) {
+					candidates = append(candidates, v)
+				}
+			}
+		})
+	}
+	collect()
+	if len(candidates) == 0 && !goOK {
+		// the required version may be newer than known versions
+		fetched, err := r.fetchOnce(ctx)
 		if err != nil {
-			return "", "", fmt.Errorf(`failed to find "go" command which has the version %s exactly`, version)
+			return nil, err
 		}
-	} else {
-		path, err = LookupLatest(version)
-		if err != nil {
-			if mode == ModeLatest {
-				return "", "", fmt.Errorf(`failed to find "go" command that has major version %s: %w`, MajorVersion(version), err)
-			}
-			path = "go" // ModeFallback
+		if fetched {
+			collect()
 		}
 	}
 
-	if path == "go" {
-		goVer, err := CurrentVersion()
-		if err != nil {
-			return "", "", fmt.Errorf(`failed to get "go" version`)
+	// better returns whether v is preferred to w
+	better := func(v, w string) bool {
+		if newest {
+			return Version(v).Compare(Version(w)) > 0
 		}
-		return path, goVer, nil
+		return Version(v).Compare(Version(w)) < 0
 	}
-	return path, filepath.Base(path), nil
+	sort.Slice(candidates, func(i, j int) bool {
+		return better(candidates[i], candidates[j])
+	})
+
+	for _, c := range candidates {
+		if goOK && !better(c, cur) {
+			return goCommand(cur), nil
+		}
+		found, err := r.find(ctx, c)
+		if err == nil {
+			return found, nil
+		}
+	}
+	if goOK {
+		return goCommand(cur), nil
+	}
+	return nil, ErrNotFound
 }
 
 // DetermineFromModuleGoVersion determines go command with the version from go.mod, and returns its path and actual version.
-// Every mode uses LookupLatest. In ModeFallback, if no command was found, fallbacks to "go"command.
+// Modes are applied in the same order as Determine, as follows.
+//   - ModeExact behaves as ModeLatest
+//   - ModeLatest determines command from the family of the toolchain directive first, and then from the family of the go directive.
+//     The chosen command always satisfies ModuleVersion.Accepts
+//   - ModeMinimum and ModeNewest determine the oldest or the newest installed command which is not older than the go directive
+//   - ModeFallback fallbacks to "go" command
+//
+// Zero Mode behaves as ModeLatest.
 func DetermineFromModuleGoVersion(mode Mode) (path, ver string, _ error) {
-	modVer, err := ModuleGoVersion()
+	return DetermineFromModuleGoVersionContext(context.Background(), mode)
+}
+
+// DetermineFromModuleGoVersion determines the command for "go.mod" found from the working directory of r.
+func (r *Resolver) DetermineFromModuleGoVersion(mode Mode) (path, ver string, _ error) {
+	return r.DetermineFromModuleGoVersionContext(context.Background(), mode)
+}
+
+// DetermineFromModuleGoVersionContext is like DetermineFromModuleGoVersion, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func DetermineFromModuleGoVersionContext(ctx context.Context, mode Mode) (path, ver string, _ error) {
+	return defaultResolver.DetermineFromModuleGoVersionContext(ctx, mode)
+}
+
+// DetermineFromModuleGoVersionContext is like r.DetermineFromModuleGoVersion, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func (r *Resolver) DetermineFromModuleGoVersionContext(ctx context.Context, mode Mode) (path, ver string, _ error) {
+	return commandResult(r.DetermineCommandFromModuleGoVersionContext(ctx, mode))
+}
+
+// DetermineCommandFromModuleGoVersion is like DetermineFromModuleGoVersion, but reports where the command is found.
+func DetermineCommandFromModuleGoVersion(mode Mode) (*Command, error) {
+	return DetermineCommandFromModuleGoVersionContext(context.Background(), mode)
+}
+
+// DetermineCommandFromModuleGoVersion is like r.DetermineFromModuleGoVersion, but reports where the command is found.
+func (r *Resolver) DetermineCommandFromModuleGoVersion(mode Mode) (*Command, error) {
+	return r.DetermineCommandFromModuleGoVersionContext(context.Background(), mode)
+}
+
+// DetermineCommandFromModuleGoVersionContext is like DetermineCommandFromModuleGoVersion, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func DetermineCommandFromModuleGoVersionContext(ctx context.Context, mode Mode) (*Command, error) {
+	return defaultResolver.DetermineCommandFromModuleGoVersionContext(ctx, mode)
+}
+
+// DetermineCommandFromModuleGoVersionContext is like r.DetermineCommandFromModuleGoVersion, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func (r *Resolver) DetermineCommandFromModuleGoVersionContext(ctx context.Context, mode Mode) (*Command, error) {
+	modVer, err := r.ModuleGoVersionContext(ctx)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to read go.mod: %w", err)
+		return nil, fmt.Errorf("failed to read go.mod: %w", err)
 	}
-	path, err = LookupLatest(modVer)
+	return r.determineRequirement(ctx, modVer, mode, "go.mod")
+}
+
+// requirement is implemented by ModuleVersion and *WorkVersion.
+type requirement interface {
+	// Accepts reports whether the version is in the acceptable families, and is not too old.
+	Accepts(version Version) bool
+
+	// allows reports whether the version is not too old, regardless of its family.
+	allows(version Version) bool
+
+	goVersion() ModuleVersion
+}
+
+// determineRequirement determines go command that satisfies req.
+func (r *Resolver) determineRequirement(ctx context.Context, req requirement, mode Mode, file string) (*Command, error) {
+	mode, err := mode.normalize(ModeLatest)
 	if err != nil {
-		switch mode {
-		case ModeFallback:
-			goVer, _ := CurrentVersion() // CurrentVersion is already called and succeeded in LookupLatest
-			return "go", goVer, nil
-		default: // ModeExact, ModeLatest
-			return "", "", fmt.Errorf(`failed to find "go" command that has major version %s: %w`, modVer, err)
+		return nil, err
+	}
+	if mode&ModeExact != 0 {
+		// the requirement is satisfied by the family, so ModeExact behaves as ModeLatest as before
+		mode = mode&^ModeExact | ModeLatest
+	}
+	modVer := req.goVersion()
+
+	var c *Command
+	var errs []error
+	if mode&ModeLatest != 0 {
+		c, err = r.lookupRequirement(ctx, req, mode.accept(req.Accepts))
+		if err == nil {
+			return r.determined(ctx, c)
 		}
+		errs = append(errs, fmt.Errorf(`failed to find "go" command that satisfies go version %s in %s: %w`, modVer.MinimumToolchain, file, err))
 	}
-	if path == "go" {
-		goVer, _ := CurrentVersion()
-		return path, goVer, nil
+	if mode&(ModeMinimum|ModeNewest) != 0 {
+		c, err = r.lookupInstalled(ctx, mode.accept(req.allows), mode&ModeNewest != 0)
+		if err == nil {
+			return r.determined(ctx, c)
+		}
+		errs = append(errs, fmt.Errorf(`failed to find "go" command whose version is %s or later as required by %s: %w`, modVer.MinimumToolchain, file, err))
+	}
+	if mode&ModeFallback != 0 {
+		return r.determined(ctx, goCommand(""))
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (r *Resolver) lookupRequirement(ctx context.Context, rq requirement, accept func(Version) bool) (*Command, error) {
+	req := rq.goVersion()
+	var families []Version
+	if req.Toolchain != "" {
+		families = append(families, req.Toolchain.Family())
+	}
+	if len(families) == 0 || families[0] != req.Language {
+		families = append(families, req.Language)
+	}
+
+	var err error
+	for _, family := range families {
+		var c *Command
+		c, err = r.lookupLatest(ctx, family, accept)
+		if err == nil {
+			return c, nil
+		}
 	}
-	return path, filepath.Base(path), nil
+	return nil, err
 }
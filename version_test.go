@@ -2,8 +2,12 @@ package gocmd
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -97,8 +101,7 @@ func currentVersion(t *testing.T) string {
 	if err != nil {
 		t.Fatal(err)
 	}
-	prefix := versionRe.FindString(cur)
-	if prefix < "go1.19" {
+	if Version(cur).Family().Compare("go1.19") < 0 {
 		t.Skipf("test skipped because version of go command is less than go1.19: %s", cur)
 	}
 	return cur
@@ -178,7 +181,7 @@ func TestFindCandidates(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
-	candidates := findCandidates(version)
+	candidates := defaultResolver.findCandidates(version)
 
 	diff := cmp.Diff([]string{
 		"go1.15.15",
@@ -206,6 +209,48 @@ func TestFindCandidates(t *testing.T) {
 	}
 }
 
+// every family "go1.N" must not match the family "go1.NM", e.g. go1.2 and go1.21
+func TestFindCandidates_familyCollision(t *testing.T) {
+	t.Parallel()
+
+	families := map[Version]bool{}
+	defaultResolver.catalog.Versions(func(versions map[string]bool) {
+		for v := range versions {
+			families[Version(v).Family()] = true
+		}
+	})
+
+	var checked int
+	for long := range families {
+		if long.Minor() < 10 {
+			continue
+		}
+		// e.g. go1.21 collides with go1.2 by prefix
+		short := Version(fmt.Sprintf("go%d.%d", long.Major(), long.Minor()/10))
+		if !families[short] {
+			continue
+		}
+		checked++
+
+		for _, c := range defaultResolver.findCandidates(string(short)) {
+			if Version(c).Family() != short {
+				t.Errorf("%s: unexpected candidate %s", short, c)
+			}
+		}
+		for _, c := range defaultResolver.findCandidates(string(long)) {
+			if Version(c).Family() != long {
+				t.Errorf("%s: unexpected candidate %s", long, c)
+			}
+			if sameFamily(c, string(short)) {
+				t.Errorf("%s and %s expected to be different families", c, short)
+			}
+		}
+	}
+	if checked == 0 {
+		t.Fatal("no collision checked")
+	}
+}
+
 func TestLookupLatest(t *testing.T) {
 	t.Parallel()
 	checkPrerequisites(t)
@@ -229,7 +274,7 @@ func TestLookupLatest(t *testing.T) {
 	if path != "go" {
 		t.Fatalf("expected path: %q, got path: %q", "go", path)
 	}
-	assertCommand(t, path, versionRe.FindString(cur))
+	assertCommand(t, path, MajorVersion(cur))
 
 	path, err = LookupLatest("go1.18")
 	if err != nil {
@@ -253,7 +298,7 @@ func TestDetermine(t *testing.T) {
 		if gotVer != wantVer {
 			t.Fatalf("unexpected version: want: %s, got %s", wantVer, gotVer)
 		}
-		err := checkCommandVersion(path, gotVer)
+		err := defaultResolver.checkCommandVersion(context.Background(), path, gotVer)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -331,7 +376,7 @@ func TestDetermineFromModuleGoVersion(t *testing.T) {
 		if MajorVersion(gotVer) != wantVer {
 			t.Fatalf("unexpected version: want: %s, got %s", wantVer, gotVer)
 		}
-		err := checkCommandVersion(path, gotVer)
+		err := defaultResolver.checkCommandVersion(context.Background(), path, gotVer)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -340,9 +385,9 @@ func TestDetermineFromModuleGoVersion(t *testing.T) {
 	cur := currentVersion(t)
 
 	t.Run("valid", func(t *testing.T) {
-		chdir(t, "testdata/valid")
+		r := NewResolver(WithDir("testdata/valid"))
 
-		path, ver, err := DetermineFromModuleGoVersion(0)
+		path, ver, err := r.DetermineFromModuleGoVersion(0)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -350,36 +395,214 @@ func TestDetermineFromModuleGoVersion(t *testing.T) {
 	})
 
 	t.Run("invalid", func(t *testing.T) {
-		chdir(t, "testdata/invalid")
+		r := NewResolver(WithDir("testdata/invalid"))
 
-		_, _, err := DetermineFromModuleGoVersion(0)
+		_, _, err := r.DetermineFromModuleGoVersion(0)
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 	})
 
 	t.Run("future", func(t *testing.T) {
-		chdir(t, "testdata/future")
+		r := NewResolver(WithDir("testdata/future"))
+
+		_, _, err := r.DetermineFromModuleGoVersion(ModeLatest)
+		if err == nil {
+			t.Fatal("unexpected success")
+		}
 
-		_, _, err := DetermineFromModuleGoVersion(ModeLatest)
+		// with GOTOOLCHAIN=auto, "go" command fails to switch to go1.99.0, which is not released
+		_, _, err = NewResolver(WithDir("testdata/future"), WithGOTOOLCHAIN("auto")).DetermineFromModuleGoVersion(ModeFallback)
 		if err == nil {
 			t.Fatal("unexpected success")
 		}
 
-		path, ver, err := DetermineFromModuleGoVersion(ModeFallback)
+		path, ver, err := NewResolver(WithDir("testdata/future"), WithGOTOOLCHAIN("local")).DetermineFromModuleGoVersion(ModeFallback)
 		if err != nil {
 			t.Fatal(err)
 		}
-		assert(t, path, ver, cur)
+		if path != "go" || ver != cur {
+			t.Fatalf("unexpected command: want: go %s, got %s %s", cur, path, ver)
+		}
 	})
 
 	t.Run("old", func(t *testing.T) {
-		chdir(t, "testdata/old")
+		r := NewResolver(WithDir("testdata/old"))
 
-		path, ver, err := DetermineFromModuleGoVersion(0)
+		path, ver, err := r.DetermineFromModuleGoVersion(0)
 		if err != nil {
 			t.Fatal(err)
 		}
 		assert(t, path, ver, "go1.18")
 	})
 }
+
+// installFakeGo puts fake executables of the given versions and the real "go" command into PATH of the returned options.
+func installFakeGo(t *testing.T, versions ...string) (ResolverOption, map[string]string) {
+	t.Helper()
+
+	goroot := filepath.Dir(mustLookPath(t, "go"))
+	dir := t.TempDir()
+	paths := map[string]string{}
+	for _, v := range versions {
+		path := fakeGo(t, v, "", v)
+		err := os.Rename(path, filepath.Join(dir, v))
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths[v] = filepath.Join(dir, v)
+	}
+	return WithPATH(dir + string(os.PathListSeparator) + goroot), paths
+}
+
+func TestDetermine_minimum(t *testing.T) {
+	cur := currentVersion(t)
+	if Version(cur).Compare("go1.23.0") < 0 {
+		t.Skipf("test skipped because version of go command is less than go1.23.0: %s", cur)
+	}
+	withPATH, paths := installFakeGo(t, "go1.21.5", "go1.22.3")
+	r := NewResolver(withPATH)
+
+	for _, i := range []struct {
+		version  string
+		mode     Mode
+		path     string
+		expected string
+	}{
+		{version: "go1.21.0", mode: ModeMinimum, path: paths["go1.21.5"], expected: "go1.21.5"},
+		{version: "go1.21.6", mode: ModeMinimum, path: paths["go1.22.3"], expected: "go1.22.3"},
+		{version: "go1.21.0", mode: ModeNewest, path: "go", expected: cur},
+		{version: "go1.23.0", mode: ModeMinimum, path: "go", expected: cur},
+	} {
+		path, ver, err := r.Determine(i.version, i.mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != i.path || ver != i.expected {
+			t.Errorf("%s(%s): unexpected result: path=%s, version=%s", i.version, i.mode, path, ver)
+		}
+	}
+
+	t.Run("module", func(t *testing.T) {
+		// go.mod declares "go 1.21.0" and "toolchain go1.22.3"
+		r := NewResolver(withPATH, WithDir("testdata/toolchain"))
+
+		path, ver, err := r.DetermineFromModuleGoVersion(ModeMinimum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != paths["go1.21.5"] || ver != "go1.21.5" {
+			t.Fatalf("unexpected result: path=%s, version=%s", path, ver)
+		}
+
+		path, ver, err = r.DetermineFromModuleGoVersion(ModeNewest)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if path != "go" || ver != cur {
+			t.Fatalf("unexpected result: path=%s, version=%s", path, ver)
+		}
+
+		c, err := r.DetermineCommandFromModuleGoVersion(ModeMinimum)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Path != paths["go1.21.5"] || c.Version != "go1.21.5" || c.Location != LocationPATH {
+			t.Fatalf("unexpected command: %+v", c)
+		}
+	})
+}
+
+func TestDetermine_minimumNotFound(t *testing.T) {
+	currentVersion(t)
+	withPATH, _ := installFakeGo(t, "go1.21.5")
+	r := NewResolver(withPATH)
+
+	// versions older than go1.2 are known, but none of them is installed
+	_, err := r.lookupInstalled(context.Background(), func(v Version) bool {
+		return v.Compare("go1.2") < 0
+	}, false)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestDetermine_composite(t *testing.T) {
+	cur := currentVersion(t)
+	if Version(cur).Compare("go1.23.0") < 0 {
+		t.Skipf("test skipped because version of go command is less than go1.23.0: %s", cur)
+	}
+	// only a prerelease is installed in the family go1.22
+	withPATH, paths := installFakeGo(t, "go1.22rc2")
+	r := NewResolver(withPATH)
+
+	for _, i := range []struct {
+		version  string
+		mode     Mode
+		path     string
+		expected string
+		error    error
+	}{
+		{version: "go1.22.0", mode: ModeExact | ModeLatest, path: paths["go1.22rc2"], expected: "go1.22rc2"},
+		{version: "go1.22.0", mode: ModeLatest | ModeStable, error: ErrNotFound},
+		{version: "go1.22.0", mode: ModeLatest | ModeStable | ModeFallback, path: "go", expected: cur},
+		{version: "go1.22rc1", mode: ModeMinimum, path: paths["go1.22rc2"], expected: "go1.22rc2"},
+		{version: "go1.22rc1", mode: ModeMinimum | ModeStable, path: "go", expected: cur},
+		{version: "go1.22rc1", mode: ModeExact | ModeNewest, path: "go", expected: cur},
+		{version: "go1.22.0", mode: ModeExact, error: ErrNotFound},
+		{version: "go1.22.0", mode: ModeMinimum | ModeNewest, error: ErrInvalidMode},
+	} {
+		path, ver, err := r.Determine(i.version, i.mode)
+		if i.error != nil {
+			if !errors.Is(err, i.error) {
+				t.Errorf("%s(%s): expected error %v, got %v", i.version, i.mode, i.error, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s(%s): %s", i.version, i.mode, err)
+		}
+		if path != i.path || ver != i.expected {
+			t.Errorf("%s(%s): unexpected result: path=%s, version=%s", i.version, i.mode, path, ver)
+		}
+	}
+}
+
+func TestDetermineFromModuleGoVersion_exact(t *testing.T) {
+	currentVersion(t)
+	goroot := filepath.Dir(mustLookPath(t, "go"))
+	dir := t.TempDir()
+	placeFakeGo(t, filepath.Join(dir, "go1.22.5"), "go1.22.5")
+	// go.mod declares "go 1.21.0" and "toolchain go1.22.3"
+	r := NewResolver(
+		WithDir(filepath.Join("testdata", "toolchain")),
+		WithPATH(dir+string(os.PathListSeparator)+goroot),
+		WithSearchLocations(LocationPATH),
+	)
+
+	// ModeExact behaves as ModeLatest, and does not require go1.22.3 exactly
+	for _, mode := range []Mode{ModeExact, ModeExact | ModeLatest, ModeLatest} {
+		path, ver, err := r.DetermineFromModuleGoVersion(mode)
+		if err != nil {
+			t.Fatalf("%s: %s", mode, err)
+		}
+		if want := filepath.Join(dir, "go1.22.5"); path != want || ver != "go1.22.5" {
+			t.Fatalf("%s: unexpected result: path=%s, version=%s", mode, path, ver)
+		}
+	}
+
+	// no fallback
+	r = NewResolver(
+		WithDir(filepath.Join("testdata", "toolchain")),
+		WithPATH(goroot),
+		WithSearchLocations(LocationPATH),
+	)
+	cur := currentVersion(t)
+	if (ModuleVersion{Language: "go1.21", MinimumToolchain: "go1.21.0", Toolchain: "go1.22.3"}).Accepts(Version(cur)) {
+		t.Skipf("test skipped because go command satisfies go.mod: %s", cur)
+	}
+	_, _, err := r.DetermineFromModuleGoVersion(ModeExact)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
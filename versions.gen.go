@@ -0,0 +1,314 @@
+// Code generated by genvers. DO NOT EDIT.
+package gocmd
+
+// Releases of Go in the embedded release index.
+// Referring to them instead of string literals, pinned versions are checked at compile time.
+const (
+	Go1         Version = "go1"
+	Go1_2_2     Version = "go1.2.2"
+	Go1_3rc1    Version = "go1.3rc1"
+	Go1_3rc2    Version = "go1.3rc2"
+	Go1_3       Version = "go1.3"
+	Go1_3_1     Version = "go1.3.1"
+	Go1_3_2     Version = "go1.3.2"
+	Go1_3_3     Version = "go1.3.3"
+	Go1_4beta1  Version = "go1.4beta1"
+	Go1_4rc1    Version = "go1.4rc1"
+	Go1_4rc2    Version = "go1.4rc2"
+	Go1_4       Version = "go1.4"
+	Go1_4_1     Version = "go1.4.1"
+	Go1_4_2     Version = "go1.4.2"
+	Go1_4_3     Version = "go1.4.3"
+	Go1_5beta1  Version = "go1.5beta1"
+	Go1_5beta2  Version = "go1.5beta2"
+	Go1_5beta3  Version = "go1.5beta3"
+	Go1_5rc1    Version = "go1.5rc1"
+	Go1_5       Version = "go1.5"
+	Go1_5_1     Version = "go1.5.1"
+	Go1_5_2     Version = "go1.5.2"
+	Go1_5_3     Version = "go1.5.3"
+	Go1_5_4     Version = "go1.5.4"
+	Go1_6beta1  Version = "go1.6beta1"
+	Go1_6beta2  Version = "go1.6beta2"
+	Go1_6rc1    Version = "go1.6rc1"
+	Go1_6rc2    Version = "go1.6rc2"
+	Go1_6       Version = "go1.6"
+	Go1_6_1     Version = "go1.6.1"
+	Go1_6_2     Version = "go1.6.2"
+	Go1_6_3     Version = "go1.6.3"
+	Go1_6_4     Version = "go1.6.4"
+	Go1_7beta1  Version = "go1.7beta1"
+	Go1_7beta2  Version = "go1.7beta2"
+	Go1_7rc1    Version = "go1.7rc1"
+	Go1_7rc2    Version = "go1.7rc2"
+	Go1_7rc3    Version = "go1.7rc3"
+	Go1_7rc4    Version = "go1.7rc4"
+	Go1_7rc5    Version = "go1.7rc5"
+	Go1_7rc6    Version = "go1.7rc6"
+	Go1_7       Version = "go1.7"
+	Go1_7_1     Version = "go1.7.1"
+	Go1_7_3     Version = "go1.7.3"
+	Go1_7_4     Version = "go1.7.4"
+	Go1_7_5     Version = "go1.7.5"
+	Go1_7_6     Version = "go1.7.6"
+	Go1_8beta1  Version = "go1.8beta1"
+	Go1_8beta2  Version = "go1.8beta2"
+	Go1_8rc1    Version = "go1.8rc1"
+	Go1_8rc2    Version = "go1.8rc2"
+	Go1_8rc3    Version = "go1.8rc3"
+	Go1_8       Version = "go1.8"
+	Go1_8_1     Version = "go1.8.1"
+	Go1_8_2     Version = "go1.8.2"
+	Go1_8_3     Version = "go1.8.3"
+	Go1_8_4     Version = "go1.8.4"
+	Go1_8_5     Version = "go1.8.5"
+	Go1_8_6     Version = "go1.8.6"
+	Go1_8_7     Version = "go1.8.7"
+	Go1_9beta1  Version = "go1.9beta1"
+	Go1_9beta2  Version = "go1.9beta2"
+	Go1_9rc1    Version = "go1.9rc1"
+	Go1_9rc2    Version = "go1.9rc2"
+	Go1_9       Version = "go1.9"
+	Go1_9_1     Version = "go1.9.1"
+	Go1_9_2rc2  Version = "go1.9.2rc2"
+	Go1_9_2     Version = "go1.9.2"
+	Go1_9_3     Version = "go1.9.3"
+	Go1_9_4     Version = "go1.9.4"
+	Go1_9_5     Version = "go1.9.5"
+	Go1_9_6     Version = "go1.9.6"
+	Go1_9_7     Version = "go1.9.7"
+	Go1_10beta1 Version = "go1.10beta1"
+	Go1_10beta2 Version = "go1.10beta2"
+	Go1_10rc1   Version = "go1.10rc1"
+	Go1_10rc2   Version = "go1.10rc2"
+	Go1_10      Version = "go1.10"
+	Go1_10_1    Version = "go1.10.1"
+	Go1_10_2    Version = "go1.10.2"
+	Go1_10_3    Version = "go1.10.3"
+	Go1_10_4    Version = "go1.10.4"
+	Go1_10_5    Version = "go1.10.5"
+	Go1_10_6    Version = "go1.10.6"
+	Go1_10_7    Version = "go1.10.7"
+	Go1_10_8    Version = "go1.10.8"
+	Go1_11beta1 Version = "go1.11beta1"
+	Go1_11beta2 Version = "go1.11beta2"
+	Go1_11beta3 Version = "go1.11beta3"
+	Go1_11rc1   Version = "go1.11rc1"
+	Go1_11rc2   Version = "go1.11rc2"
+	Go1_11      Version = "go1.11"
+	Go1_11_1    Version = "go1.11.1"
+	Go1_11_2    Version = "go1.11.2"
+	Go1_11_3    Version = "go1.11.3"
+	Go1_11_4    Version = "go1.11.4"
+	Go1_11_5    Version = "go1.11.5"
+	Go1_11_6    Version = "go1.11.6"
+	Go1_11_7    Version = "go1.11.7"
+	Go1_11_8    Version = "go1.11.8"
+	Go1_11_9    Version = "go1.11.9"
+	Go1_11_10   Version = "go1.11.10"
+	Go1_11_11   Version = "go1.11.11"
+	Go1_11_12   Version = "go1.11.12"
+	Go1_11_13   Version = "go1.11.13"
+	Go1_12beta1 Version = "go1.12beta1"
+	Go1_12beta2 Version = "go1.12beta2"
+	Go1_12rc1   Version = "go1.12rc1"
+	Go1_12      Version = "go1.12"
+	Go1_12_1    Version = "go1.12.1"
+	Go1_12_2    Version = "go1.12.2"
+	Go1_12_3    Version = "go1.12.3"
+	Go1_12_4    Version = "go1.12.4"
+	Go1_12_5    Version = "go1.12.5"
+	Go1_12_6    Version = "go1.12.6"
+	Go1_12_7    Version = "go1.12.7"
+	Go1_12_8    Version = "go1.12.8"
+	Go1_12_9    Version = "go1.12.9"
+	Go1_12_10   Version = "go1.12.10"
+	Go1_12_11   Version = "go1.12.11"
+	Go1_12_12   Version = "go1.12.12"
+	Go1_12_13   Version = "go1.12.13"
+	Go1_12_14   Version = "go1.12.14"
+	Go1_12_15   Version = "go1.12.15"
+	Go1_12_16   Version = "go1.12.16"
+	Go1_12_17   Version = "go1.12.17"
+	Go1_13beta1 Version = "go1.13beta1"
+	Go1_13rc1   Version = "go1.13rc1"
+	Go1_13rc2   Version = "go1.13rc2"
+	Go1_13      Version = "go1.13"
+	Go1_13_1    Version = "go1.13.1"
+	Go1_13_2    Version = "go1.13.2"
+	Go1_13_3    Version = "go1.13.3"
+	Go1_13_4    Version = "go1.13.4"
+	Go1_13_5    Version = "go1.13.5"
+	Go1_13_6    Version = "go1.13.6"
+	Go1_13_7    Version = "go1.13.7"
+	Go1_13_8    Version = "go1.13.8"
+	Go1_13_9    Version = "go1.13.9"
+	Go1_13_10   Version = "go1.13.10"
+	Go1_13_11   Version = "go1.13.11"
+	Go1_13_12   Version = "go1.13.12"
+	Go1_13_13   Version = "go1.13.13"
+	Go1_13_14   Version = "go1.13.14"
+	Go1_13_15   Version = "go1.13.15"
+	Go1_14beta1 Version = "go1.14beta1"
+	Go1_14rc1   Version = "go1.14rc1"
+	Go1_14      Version = "go1.14"
+	Go1_14_1    Version = "go1.14.1"
+	Go1_14_2    Version = "go1.14.2"
+	Go1_14_3    Version = "go1.14.3"
+	Go1_14_4    Version = "go1.14.4"
+	Go1_14_5    Version = "go1.14.5"
+	Go1_14_6    Version = "go1.14.6"
+	Go1_14_7    Version = "go1.14.7"
+	Go1_14_8    Version = "go1.14.8"
+	Go1_14_9    Version = "go1.14.9"
+	Go1_14_10   Version = "go1.14.10"
+	Go1_14_11   Version = "go1.14.11"
+	Go1_14_12   Version = "go1.14.12"
+	Go1_14_13   Version = "go1.14.13"
+	Go1_14_14   Version = "go1.14.14"
+	Go1_14_15   Version = "go1.14.15"
+	Go1_15beta1 Version = "go1.15beta1"
+	Go1_15rc1   Version = "go1.15rc1"
+	Go1_15rc2   Version = "go1.15rc2"
+	Go1_15      Version = "go1.15"
+	Go1_15_1    Version = "go1.15.1"
+	Go1_15_2    Version = "go1.15.2"
+	Go1_15_3    Version = "go1.15.3"
+	Go1_15_4    Version = "go1.15.4"
+	Go1_15_5    Version = "go1.15.5"
+	Go1_15_6    Version = "go1.15.6"
+	Go1_15_7    Version = "go1.15.7"
+	Go1_15_8    Version = "go1.15.8"
+	Go1_15_9    Version = "go1.15.9"
+	Go1_15_10   Version = "go1.15.10"
+	Go1_15_11   Version = "go1.15.11"
+	Go1_15_12   Version = "go1.15.12"
+	Go1_15_13   Version = "go1.15.13"
+	Go1_15_14   Version = "go1.15.14"
+	Go1_15_15   Version = "go1.15.15"
+	Go1_16beta1 Version = "go1.16beta1"
+	Go1_16rc1   Version = "go1.16rc1"
+	Go1_16      Version = "go1.16"
+	Go1_16_1    Version = "go1.16.1"
+	Go1_16_2    Version = "go1.16.2"
+	Go1_16_3    Version = "go1.16.3"
+	Go1_16_4    Version = "go1.16.4"
+	Go1_16_5    Version = "go1.16.5"
+	Go1_16_6    Version = "go1.16.6"
+	Go1_16_7    Version = "go1.16.7"
+	Go1_16_8    Version = "go1.16.8"
+	Go1_16_9    Version = "go1.16.9"
+	Go1_16_10   Version = "go1.16.10"
+	Go1_16_11   Version = "go1.16.11"
+	Go1_16_12   Version = "go1.16.12"
+	Go1_16_13   Version = "go1.16.13"
+	Go1_16_14   Version = "go1.16.14"
+	Go1_16_15   Version = "go1.16.15"
+	Go1_17beta1 Version = "go1.17beta1"
+	Go1_17rc1   Version = "go1.17rc1"
+	Go1_17rc2   Version = "go1.17rc2"
+	Go1_17      Version = "go1.17"
+	Go1_17_1    Version = "go1.17.1"
+	Go1_17_2    Version = "go1.17.2"
+	Go1_17_3    Version = "go1.17.3"
+	Go1_17_4    Version = "go1.17.4"
+	Go1_17_5    Version = "go1.17.5"
+	Go1_17_6    Version = "go1.17.6"
+	Go1_17_7    Version = "go1.17.7"
+	Go1_17_8    Version = "go1.17.8"
+	Go1_17_9    Version = "go1.17.9"
+	Go1_17_10   Version = "go1.17.10"
+	Go1_17_11   Version = "go1.17.11"
+	Go1_17_12   Version = "go1.17.12"
+	Go1_17_13   Version = "go1.17.13"
+	Go1_18beta1 Version = "go1.18beta1"
+	Go1_18beta2 Version = "go1.18beta2"
+	Go1_18rc1   Version = "go1.18rc1"
+	Go1_18      Version = "go1.18"
+	Go1_18_1    Version = "go1.18.1"
+	Go1_18_2    Version = "go1.18.2"
+	Go1_18_3    Version = "go1.18.3"
+	Go1_18_4    Version = "go1.18.4"
+	Go1_18_5    Version = "go1.18.5"
+	Go1_18_6    Version = "go1.18.6"
+	Go1_18_7    Version = "go1.18.7"
+	Go1_18_8    Version = "go1.18.8"
+	Go1_18_9    Version = "go1.18.9"
+	Go1_18_10   Version = "go1.18.10"
+	Go1_19beta1 Version = "go1.19beta1"
+	Go1_19rc1   Version = "go1.19rc1"
+	Go1_19rc2   Version = "go1.19rc2"
+	Go1_19      Version = "go1.19"
+	Go1_19_1    Version = "go1.19.1"
+	Go1_19_2    Version = "go1.19.2"
+	Go1_19_3    Version = "go1.19.3"
+	Go1_19_4    Version = "go1.19.4"
+	Go1_19_5    Version = "go1.19.5"
+	Go1_19_6    Version = "go1.19.6"
+	Go1_19_7    Version = "go1.19.7"
+	Go1_19_8    Version = "go1.19.8"
+	Go1_19_9    Version = "go1.19.9"
+	Go1_19_10   Version = "go1.19.10"
+	Go1_19_11   Version = "go1.19.11"
+	Go1_19_12   Version = "go1.19.12"
+	Go1_19_13   Version = "go1.19.13"
+	Go1_20rc1   Version = "go1.20rc1"
+	Go1_20rc2   Version = "go1.20rc2"
+	Go1_20rc3   Version = "go1.20rc3"
+	Go1_20      Version = "go1.20"
+	Go1_20_1    Version = "go1.20.1"
+	Go1_20_2    Version = "go1.20.2"
+	Go1_20_3    Version = "go1.20.3"
+	Go1_20_4    Version = "go1.20.4"
+	Go1_20_5    Version = "go1.20.5"
+	Go1_20_6    Version = "go1.20.6"
+	Go1_20_7    Version = "go1.20.7"
+	Go1_20_8    Version = "go1.20.8"
+	Go1_20_9    Version = "go1.20.9"
+	Go1_20_10   Version = "go1.20.10"
+	Go1_20_11   Version = "go1.20.11"
+	Go1_20_12   Version = "go1.20.12"
+	Go1_20_13   Version = "go1.20.13"
+	Go1_20_14   Version = "go1.20.14"
+	Go1_21rc2   Version = "go1.21rc2"
+	Go1_21rc3   Version = "go1.21rc3"
+	Go1_21rc4   Version = "go1.21rc4"
+	Go1_21_0    Version = "go1.21.0"
+	Go1_21_1    Version = "go1.21.1"
+	Go1_21_2    Version = "go1.21.2"
+	Go1_21_3    Version = "go1.21.3"
+	Go1_21_4    Version = "go1.21.4"
+	Go1_21_5    Version = "go1.21.5"
+	Go1_21_6    Version = "go1.21.6"
+	Go1_21_7    Version = "go1.21.7"
+	Go1_21_8    Version = "go1.21.8"
+	Go1_21_9    Version = "go1.21.9"
+	Go1_21_10   Version = "go1.21.10"
+	Go1_21_11   Version = "go1.21.11"
+	Go1_21_12   Version = "go1.21.12"
+	Go1_21_13   Version = "go1.21.13"
+	Go1_22rc1   Version = "go1.22rc1"
+	Go1_22rc2   Version = "go1.22rc2"
+	Go1_22_0    Version = "go1.22.0"
+	Go1_22_1    Version = "go1.22.1"
+	Go1_22_2    Version = "go1.22.2"
+	Go1_22_3    Version = "go1.22.3"
+	Go1_22_4    Version = "go1.22.4"
+	Go1_22_5    Version = "go1.22.5"
+	Go1_22_6    Version = "go1.22.6"
+	Go1_22_7    Version = "go1.22.7"
+	Go1_22_8    Version = "go1.22.8"
+	Go1_22_9    Version = "go1.22.9"
+	Go1_23rc1   Version = "go1.23rc1"
+	Go1_23rc2   Version = "go1.23rc2"
+	Go1_23_0    Version = "go1.23.0"
+	Go1_23_1    Version = "go1.23.1"
+	Go1_23_2    Version = "go1.23.2"
+	Go1_23_3    Version = "go1.23.3"
+)
+
+// Latest is the newest release in the embedded release index, including betas and release candidates.
+const Latest = Go1_23_3
+
+// LatestStable is the newest stable release in the embedded release index.
+const LatestStable = Go1_23_3
@@ -0,0 +1,192 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+)
+
+// WorkVersion is the Go version requirement declared in "go.work" and the modules used in the workspace.
+type WorkVersion struct {
+	// File is the path of "go.work".
+	File string
+
+	// ModuleVersion is read from the go directive and the toolchain directive of "go.work".
+	ModuleVersion
+
+	// Modules is the Go version requirement of each module in use directives, keyed by its directory.
+	Modules map[string]ModuleVersion
+}
+
+// Accepts reports whether the workspace can be built with the go command of the given version.
+// In addition to ModuleVersion.Accepts of "go.work", the version must not be older than the go directive of every used module.
+func (w *WorkVersion) Accepts(version Version) bool {
+	return w.ModuleVersion.Accepts(version) && w.allows(version)
+}
+
+func (w *WorkVersion) allows(version Version) bool {
+	if !w.ModuleVersion.allows(version) {
+		return false
+	}
+	for _, m := range w.Modules {
+		if !m.allows(version) {
+			return false
+		}
+	}
+	return true
+}
+
+// goWorkPath returns the path from `go env GOWORK`.
+// It returns an empty string if workspace mode is disabled.
+func (r *Resolver) goWorkPath(ctx context.Context) (string, error) {
+	path, err := r.goEnv(ctx, "go", "GOWORK", nil)
+	if err != nil {
+		return "", err
+	}
+	if path == "off" {
+		return "", nil
+	}
+	return path, nil
+}
+
+// WorkGoVersion reads workspace's go version from "go.work" with the path from `go env GOWORK`.
+// So, GOWORK environment variable is honoured. If workspace mode is disabled by GOWORK=off
+// or no "go.work" is found, it returns fs.ErrNotExist.
+// "go.mod" of every module in use directives is also read.
+func WorkGoVersion() (*WorkVersion, error) {
+	return WorkGoVersionContext(context.Background())
+}
+
+// WorkGoVersion reads "go.work" found from the working directory and GOWORK of r.
+func (r *Resolver) WorkGoVersion() (*WorkVersion, error) {
+	return r.WorkGoVersionContext(context.Background())
+}
+
+// WorkGoVersionContext is like WorkGoVersion, but the given context is applied to `go env GOWORK`.
+func WorkGoVersionContext(ctx context.Context) (*WorkVersion, error) {
+	return defaultResolver.WorkGoVersionContext(ctx)
+}
+
+// WorkGoVersionContext is like r.WorkGoVersion, but the given context is applied to `go env GOWORK`.
+func (r *Resolver) WorkGoVersionContext(ctx context.Context) (*WorkVersion, error) {
+	path, err := r.goWorkPath(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return nil, fs.ErrNotExist
+	}
+	return readWorkGoVersion(path)
+}
+
+func readWorkGoVersion(path string) (*WorkVersion, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	work, err := modfile.ParseWork(path, data, nil)
+	if err != nil {
+		return nil, err
+	}
+	if work.Go == nil {
+		return nil, errors.New("invalid workspace file: go version not found")
+	}
+	var toolchain string
+	if work.Toolchain != nil {
+		toolchain = work.Toolchain.Name
+	}
+	ver, err := newModuleVersion(work.Go.Version, toolchain)
+	if err != nil {
+		return nil, err
+	}
+
+	w := &WorkVersion{
+		File:          path,
+		ModuleVersion: ver,
+		Modules:       make(map[string]ModuleVersion, len(work.Use)),
+	}
+	for _, u := range work.Use {
+		dir := u.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(filepath.Dir(path), dir)
+		}
+		modPath := filepath.Join(dir, "go.mod")
+		data, err := os.ReadFile(modPath)
+		if err != nil {
+			return nil, err
+		}
+		info, err := ParseModuleInfo(modPath, data)
+		if err != nil {
+			var e *UnknownDirectiveError
+			if !errors.As(err, &e) {
+				return nil, err
+			}
+			info = e.Info
+		}
+		w.Modules[dir] = info.ModuleVersion
+	}
+	return w, nil
+}
+
+// DetermineFromWorkspace determines go command with the version from go.work, and returns its path and actual version.
+// It is the counterpart of DetermineFromModuleGoVersion for workspace mode.
+// The chosen command always satisfies WorkVersion.Accepts, so that it can build every module in the workspace.
+// If workspace mode is disabled by GOWORK=off or no "go.work" is found, it behaves as DetermineFromModuleGoVersion.
+func DetermineFromWorkspace(mode Mode) (path, ver string, _ error) {
+	return DetermineFromWorkspaceContext(context.Background(), mode)
+}
+
+// DetermineFromWorkspace determines the command for "go.work" found from the working directory of r.
+func (r *Resolver) DetermineFromWorkspace(mode Mode) (path, ver string, _ error) {
+	return r.DetermineFromWorkspaceContext(context.Background(), mode)
+}
+
+// DetermineFromWorkspaceContext is like DetermineFromWorkspace, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func DetermineFromWorkspaceContext(ctx context.Context, mode Mode) (path, ver string, _ error) {
+	return defaultResolver.DetermineFromWorkspaceContext(ctx, mode)
+}
+
+// DetermineFromWorkspaceContext is like r.DetermineFromWorkspace, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func (r *Resolver) DetermineFromWorkspaceContext(ctx context.Context, mode Mode) (path, ver string, _ error) {
+	return commandResult(r.DetermineCommandFromWorkspaceContext(ctx, mode))
+}
+
+// DetermineCommandFromWorkspace is like DetermineFromWorkspace, but reports where the command is found.
+func DetermineCommandFromWorkspace(mode Mode) (*Command, error) {
+	return DetermineCommandFromWorkspaceContext(context.Background(), mode)
+}
+
+// DetermineCommandFromWorkspace is like r.DetermineFromWorkspace, but reports where the command is found.
+func (r *Resolver) DetermineCommandFromWorkspace(mode Mode) (*Command, error) {
+	return r.DetermineCommandFromWorkspaceContext(context.Background(), mode)
+}
+
+// DetermineCommandFromWorkspaceContext is like DetermineCommandFromWorkspace, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func DetermineCommandFromWorkspaceContext(ctx context.Context, mode Mode) (*Command, error) {
+	return defaultResolver.DetermineCommandFromWorkspaceContext(ctx, mode)
+}
+
+// DetermineCommandFromWorkspaceContext is like r.DetermineCommandFromWorkspace, but the given context is applied to
+// the fetch of the release index and every "go env" subprocess.
+func (r *Resolver) DetermineCommandFromWorkspaceContext(ctx context.Context, mode Mode) (*Command, error) {
+	workPath, err := r.goWorkPath(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+	if workPath == "" {
+		return r.DetermineCommandFromModuleGoVersionContext(ctx, mode)
+	}
+	work, err := readWorkGoVersion(workPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read go.work: %w", err)
+	}
+	return r.determineRequirement(ctx, work, mode, "go.work")
+}
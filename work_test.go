@@ -0,0 +1,140 @@
+package gocmd
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWorkGoVersion(t *testing.T) {
+	abs, err := filepath.Abs("testdata/work")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &WorkVersion{
+		File: filepath.Join(abs, "go.work"),
+		ModuleVersion: ModuleVersion{
+			Language:         "go1.22",
+			MinimumToolchain: "go1.22.0",
+		},
+		Modules: map[string]ModuleVersion{
+			filepath.Join(abs, "a"): {
+				Language:         "go1.21",
+				MinimumToolchain: "go1.21.0",
+			},
+			filepath.Join(abs, "b"): {
+				Language:         "go1.22",
+				MinimumToolchain: "go1.22.3",
+			},
+		},
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/work/a"))
+
+		work, err := r.WorkGoVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, work); diff != "" {
+			t.Fatal(diff)
+		}
+
+		// go1.22.0 satisfies go.work, but not example.com/b
+		for v, accepted := range map[Version]bool{
+			"go1.22.3":  true,
+			"go1.22.10": true,
+			"go1.22.0":  false,
+			"go1.21.5":  false,
+			"go1.23.0":  false,
+		} {
+			if work.Accepts(v) != accepted {
+				t.Errorf("%s: expected accepted=%t", v, accepted)
+			}
+		}
+	})
+
+	t.Run("GOWORK", func(t *testing.T) {
+		r := NewResolver(WithDir(t.TempDir()), WithEnv("GOWORK="+want.File))
+
+		work, err := r.WorkGoVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, work); diff != "" {
+			t.Fatal(diff)
+		}
+	})
+
+	t.Run("GOWORK=off", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/work/a"), WithEnv("GOWORK=off"))
+
+		_, err := r.WorkGoVersion()
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/valid"))
+
+		_, err := r.WorkGoVersion()
+		if !errors.Is(err, fs.ErrNotExist) {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
+
+func TestDetermineFromWorkspace(t *testing.T) {
+	cur := currentVersion(t)
+
+	t.Run("workspace", func(t *testing.T) {
+		r := NewResolver(WithDir("testdata/work/a"))
+
+		work, err := r.WorkGoVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		path, ver, err := r.DetermineFromWorkspace(ModeLatest)
+		if err == nil {
+			if !work.Accepts(Version(ver)) {
+				t.Fatalf("unexpected version %s", ver)
+			}
+			if err := r.checkCommandVersion(context.Background(), path, ver); err != nil {
+				t.Fatal(err)
+			}
+		} else if !errors.Is(err, ErrNotFound) {
+			t.Fatal(err)
+		}
+
+		path, ver, err = r.DetermineFromWorkspace(ModeFallback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !work.Accepts(Version(ver)) && (path != "go" || ver != cur) {
+			t.Fatalf("unexpected fallback: %s, %s", path, ver)
+		}
+	})
+
+	t.Run("GOWORK=off", func(t *testing.T) {
+		// go.mod of example.com/b declares "go 1.22.3"
+		r := NewResolver(WithDir("testdata/work/b"), WithEnv("GOWORK=off"))
+
+		modVer, err := r.ModuleGoVersion()
+		if err != nil {
+			t.Fatal(err)
+		}
+		path, ver, err := r.DetermineFromWorkspace(ModeFallback)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !modVer.Accepts(Version(ver)) && (path != "go" || ver != cur) {
+			t.Fatalf("unexpected fallback: %s, %s", path, ver)
+		}
+	})
+}